@@ -2,39 +2,52 @@ package mongol
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
-	CollectionIDKey          = "_id"
-	CreateIndexMethod        = "CreateIndex"
-	InsertOneMethod          = "InsertOne"
-	InsertManyMethod         = "InsertMany"
-	UpdateOneMethod          = "UpdateOne"
-	UpdateManyMethod         = "UpdateMany"
-	UpdateManyByFilterMethod = "UpdateManyByFilter"
-	ReplaceOneMethod         = "ReplaceOne"
-	ReplaceOneByIDMethod     = "ReplaceOneByID"
-	GetOneByIDMethod         = "GetOneByID"
-	GetOneByFilterMethod     = "GetOneByFilter"
-	GetManyByFilterMethod    = "GetManyByFilter"
-	FindAllByFilterMethod    = "FindAllByFilter"
-	FindManyByFilterMethod   = "FindManyByFilter"
-	UpsertOneMethod          = "UpsertOne"
-	FindAndUpdateOneMethod   = "FindAndUpdateOne"
-	DeleteManyMethod         = "DeleteMany"
-	DeleteManyByFilterMethod = "DeleteManyByFilter"
-	DeleteOneByIDMethod      = "DeleteOneByID"
-	DeleteAllMethod          = "DeleteAll"
-	CloseCursorTimeout       = time.Second * 1
-	FetchTimeout             = time.Second * 1
-	QueryTimeout             = time.Second * 1
-	FilterTimeout            = time.Second * 1
+	CollectionIDKey                    = "_id"
+	CreateIndexMethod                  = "CreateIndex"
+	InsertOneMethod                    = "InsertOne"
+	InsertManyMethod                   = "InsertMany"
+	UpdateOneMethod                    = "UpdateOne"
+	UpdateManyMethod                   = "UpdateMany"
+	UpdateManyByFilterMethod           = "UpdateManyByFilter"
+	UpdateOneWithResultMethod          = "UpdateOneWithResult"
+	UpdateManyByFilterWithResultMethod = "UpdateManyByFilterWithResult"
+	SaveMethod                         = "Save"
+	PatchOneByIDMethod                 = "PatchOneByID"
+	ReplaceOneMethod                   = "ReplaceOne"
+	ReplaceOneByIDMethod               = "ReplaceOneByID"
+	GetOneByIDMethod                   = "GetOneByID"
+	GetOneByFilterMethod               = "GetOneByFilter"
+	GetManyByFilterMethod              = "GetManyByFilter"
+	FindAllByFilterMethod              = "FindAllByFilter"
+	FindManyByFilterMethod             = "FindManyByFilter"
+	IterateMethod                      = "Iterate"
+	CountByFilterMethod                = "CountByFilter"
+	UpsertOneMethod                    = "UpsertOne"
+	FindAndUpdateOneMethod             = "FindAndUpdateOne"
+	DeleteManyMethod                   = "DeleteMany"
+	DeleteManyByFilterMethod           = "DeleteManyByFilter"
+	DeleteOneByIDMethod                = "DeleteOneByID"
+	DeleteOneMethod                    = "DeleteOne"
+	DeleteAllMethod                    = "DeleteAll"
+	BulkWriteMethod                    = "BulkWrite"
+	PopulateMethod                     = "Populate"
+	SequenceReserveMethod              = "SequenceReserve"
+	SequenceCurrentMethod              = "SequenceCurrent"
+	CloseCursorTimeout                 = time.Second * 1
+	FetchTimeout                       = time.Second * 1
+	QueryTimeout                       = time.Second * 1
+	FilterTimeout                      = time.Second * 1
 )
 
 var (
@@ -44,16 +57,6 @@ var (
 // Hook
 type Hook func(ctx context.Context) error
 
-// Client
-type Client struct {
-	mongoClient *mongo.Client
-}
-
-// GetMongoClient
-func (c *Client) MongoClient() *mongo.Client {
-	return c.mongoClient
-}
-
 // BaseCollection
 type BaseCollection struct {
 	Client         *Client
@@ -61,6 +64,23 @@ type BaseCollection struct {
 	CollectionName string
 	BeforeHooks    map[string][]Hook
 	AfterHooks     map[string][]Hook
+	IDStrategy     IDStrategy
+	Timeouts       Timeouts
+	Tenancy        *Tenancy
+	DefaultScopes  []interface{}
+	Scopes         map[string]ScopeFunc
+	Relations      map[string]Relation
+	RetryPolicy    *RetryPolicy
+	// BatchSize of Iterate() and Iterator() cursors, the server default is used if zero
+	BatchSize int32
+	// TreatNoOpAsSuccess makes UpdateOne() and UpdateManyByFilter() succeed
+	// instead of returning ErrDocumentNotModified
+	TreatNoOpAsSuccess bool
+
+	// unscoped collections ignore DefaultScopes, see Unscoped()
+	unscoped bool
+	// ownsClient is set for collections created by NewBaseCollection(), see Close()
+	ownsClient bool
 }
 
 // Document
@@ -73,23 +93,33 @@ type Document interface {
 	SetupUpdatedAt()
 }
 
-func NewClient(ctx context.Context, mongoURI string) (*Client, error) {
-	c, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
-	if err != nil {
-		return nil, err
-	}
+// NewBaseCollection() is a constructor for BaseCollection struct
+// Collections created without ClientOptions share one client per URI,
+// use Close() to release it
+func NewBaseCollection(
+	ctx context.Context,
+	mongoURI, dbName, collectionName string,
+	opts ...ClientOption,
+) (*BaseCollection, error) {
+	var (
+		client *Client
+		err    error
+	)
 
-	return &Client{mongoClient: c}, nil
-}
+	if len(opts) == 0 {
+		client, err = NewSharedClient(ctx, mongoURI)
+	} else {
+		client, err = NewClient(ctx, mongoURI, opts...)
+	}
 
-// NewBaseCollection() is a constructor for BaseCollection struct
-func NewBaseCollection(ctx context.Context, mongoURI, dbName, collectionName string) (*BaseCollection, error) {
-	client, err := NewClient(ctx, mongoURI)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewBaseCollectionWithClient(client, dbName, collectionName), nil
+	s := NewBaseCollectionWithClient(client, dbName, collectionName)
+	s.ownsClient = true
+
+	return s, nil
 }
 
 // NewBaseCollectionWithClient() is a constructor for BaseCollection struct
@@ -100,10 +130,38 @@ func NewBaseCollectionWithClient(client *Client, dbName, collectionName string)
 		CollectionName: collectionName,
 		BeforeHooks:    make(map[string][]Hook),
 		AfterHooks:     make(map[string][]Hook),
+		IDStrategy:     ObjectIDStrategy{},
+		Timeouts:       DefaultTimeouts(),
+		Scopes:         make(map[string]ScopeFunc),
+		Relations:      make(map[string]Relation),
 	}
 }
 
+type nestedCtxKey struct{}
+
+// nested() marks the context of calls made by a running operation,
+// they are not registered as in-flight operations again, so Drain() lets them finish
+func (s *BaseCollection) nested(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nestedCtxKey{}, s.Client)
+}
+
+func (s *BaseCollection) isNested(ctx context.Context) bool {
+	c, _ := ctx.Value(nestedCtxKey{}).(*Client)
+
+	return c != nil && c == s.Client
+}
+
+// runBeforeHooks() registers in-flight operation of the client and runs before hooks,
+// every successful call has to be paired with runAfterHooks()
 func (s *BaseCollection) runBeforeHooks(ctx context.Context, methodName string) error {
+	nested := s.isNested(ctx)
+
+	if !nested {
+		if err := s.Client.begin(); err != nil {
+			return err
+		}
+	}
+
 	hooks, ok := s.BeforeHooks[methodName]
 	if !ok {
 		return nil
@@ -111,6 +169,10 @@ func (s *BaseCollection) runBeforeHooks(ctx context.Context, methodName string)
 
 	for i := range hooks {
 		if err := hooks[i](ctx); err != nil {
+			if !nested {
+				s.Client.end()
+			}
+
 			return err
 		}
 	}
@@ -118,7 +180,12 @@ func (s *BaseCollection) runBeforeHooks(ctx context.Context, methodName string)
 	return nil
 }
 
+// runAfterHooks() runs after hooks and unregisters in-flight operation of the client
 func (s *BaseCollection) runAfterHooks(ctx context.Context, methodName string) error {
+	if !s.isNested(ctx) {
+		defer s.Client.end()
+	}
+
 	hooks, ok := s.AfterHooks[methodName]
 	if !ok {
 		return nil
@@ -149,6 +216,129 @@ func (s *BaseCollection) AddAfterHook(methodName string, h Hook) {
 	s.AfterHooks[methodName] = append(s.AfterHooks[methodName], h)
 }
 
+// SetIDStrategy() sets the strategy used to convert IDs of the collection documents
+func (s *BaseCollection) SetIDStrategy(st IDStrategy) {
+	s.IDStrategy = st
+}
+
+// DocumentID() returns the string form of the document key accepted by GetOneByID(),
+// the key is formatted by IDStrategy, empty string is returned for documents without a key
+func (s *BaseCollection) DocumentID(m Document) string {
+	key := documentKey(m)
+	if isZeroKey(key) {
+		return ""
+	}
+
+	id, err := s.idStrategy().Format(key)
+	if err != nil {
+		return m.GetHexID()
+	}
+
+	return id
+}
+
+func (s *BaseCollection) idStrategy() IDStrategy {
+	if s.IDStrategy == nil {
+		return ObjectIDStrategy{}
+	}
+
+	return s.IDStrategy
+}
+
+// SetTimeouts() sets timeouts of the collection operations, zero fields keep previous values
+func (s *BaseCollection) SetTimeouts(t Timeouts) {
+	s.Timeouts = s.Timeouts.Merge(t)
+}
+
+// timeouts() returns collection timeouts overridden by WithTimeouts() for the call
+func (s *BaseCollection) timeouts(ctx context.Context) Timeouts {
+	t := DefaultTimeouts().Merge(s.Timeouts)
+
+	if callTimeouts, ok := ctx.Value(timeoutsCtxKey{}).(Timeouts); ok {
+		t = t.Merge(callTimeouts)
+	}
+
+	return t
+}
+
+// ensureDocumentKey() generates a key for KeyedDocument without one
+func (s *BaseCollection) ensureDocumentKey(ctx context.Context, doc interface{}) error {
+	kd, ok := doc.(KeyedDocument)
+	if !ok || !isZeroKey(kd.GetKey()) {
+		return nil
+	}
+
+	key, err := s.idStrategy().Generate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if key == nil {
+		return ErrInvalidID
+	}
+
+	return kd.SetKey(key)
+}
+
+// setInsertedID() sets inserted ID to the document and returns the string form of the ID
+func (s *BaseCollection) setInsertedID(doc, insertedID interface{}) (string, error) {
+	id, err := s.idStrategy().Format(insertedID)
+	if err != nil {
+		return "", err
+	}
+
+	switch m := doc.(type) {
+	case KeyedDocument:
+		return id, m.SetKey(insertedID)
+	case Document:
+		return id, m.SetHexID(id)
+	default:
+		return id, nil
+	}
+}
+
+func documentKey(m Document) interface{} {
+	if kd, ok := m.(KeyedDocument); ok {
+		return kd.GetKey()
+	}
+
+	return m.GetID()
+}
+
+// setDecodedID() sets ID of the decoded document,
+// KeyedDocument receives its key during decoding so it is skipped
+func setDecodedID(m Document, raw bson.Raw) error {
+	if _, ok := m.(KeyedDocument); ok {
+		return nil
+	}
+
+	return m.SetJSONID(raw.Lookup(CollectionIDKey).Value)
+}
+
+func isZeroKey(key interface{}) bool {
+	v := reflect.ValueOf(key)
+
+	return !v.IsValid() || v.IsZero()
+}
+
+// Close() releases the client created by NewBaseCollection(),
+// clients passed to NewBaseCollectionWithClient() are closed by their owner
+func (s *BaseCollection) Close(ctx context.Context) error {
+	if !s.ownsClient {
+		return nil
+	}
+
+	s.ownsClient = false
+
+	return s.Client.Close(ctx)
+}
+
+// BSONRegistry() returns the codecs registry of the client,
+// documents are marshaled with it like the driver does
+func (s *BaseCollection) BSONRegistry() *bsoncodec.Registry {
+	return s.Client.BSONRegistry()
+}
+
 // Ping() the mongo server
 func (s *BaseCollection) Ping(ctx context.Context) error {
 	return s.Client.MongoClient().Ping(ctx, nil)
@@ -170,21 +360,32 @@ func (s *BaseCollection) MongoClient() *mongo.Client {
 }
 
 // CreateIndex creates new index
-func (s *BaseCollection) CreateIndex(ctx context.Context, k interface{}, o *options.IndexOptions) (string, error) {
+func (s *BaseCollection) CreateIndex(ctx context.Context, k interface{}, o *options.IndexOptions) (_ string, err error) {
+	defer s.wrapError(&err, CreateIndexMethod, "")
+
 	if err := s.runBeforeHooks(ctx, CreateIndexMethod); err != nil {
 		return "", err
 	}
 
 	defer s.runAfterHooks(ctx, CreateIndexMethod)
 
-	return s.Collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+	coll, err := s.ResolveCollection(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	name, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
 		Keys:    k,
 		Options: o,
 	})
+
+	return name, HandleError(err)
 }
 
 // InsertOne() inserts given Document and returns an ID of inserted document
-func (s *BaseCollection) InsertOne(ctx context.Context, m Document, opts ...*options.InsertOneOptions) (string, error) {
+func (s *BaseCollection) InsertOne(ctx context.Context, m Document, opts ...*options.InsertOneOptions) (_ string, err error) {
+	defer s.wrapError(&err, InsertOneMethod, m)
+
 	if err := s.runBeforeHooks(ctx, InsertOneMethod); err != nil {
 		return "", err
 	}
@@ -193,97 +394,245 @@ func (s *BaseCollection) InsertOne(ctx context.Context, m Document, opts ...*opt
 	m.SetupCreatedAt()
 	m.SetupUpdatedAt()
 
-	b, err := bson.Marshal(m)
+	if err := runBeforeInsert(ctx, m); err != nil {
+		return "", err
+	}
+
+	if err := s.ensureDocumentKey(ctx, m); err != nil {
+		return "", err
+	}
+
+	coll, err := s.ResolveCollection(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	res, err := s.Collection().InsertOne(ctx, b, opts...)
+	doc, err := s.scopeDocument(ctx, m)
 	if err != nil {
-		return "", HandleDuplicationErr(err)
+		return "", err
 	}
 
-	objectID, ok := res.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return "", ErrInvalidObjectID
+	b, err := bson.MarshalWithRegistry(s.BSONRegistry(), doc)
+	if err != nil {
+		return "", err
 	}
 
-	hexID := objectID.Hex()
-	if err := m.SetHexID(hexID); err != nil {
+	var res *mongo.InsertOneResult
+
+	err = s.retry(ctx, InsertOneMethod, true, func(ctx context.Context) (err error) {
+		res, err = coll.InsertOne(ctx, b, opts...)
+		return err
+	})
+	if err != nil {
+		return "", HandleError(err)
+	}
+
+	hexID, err := s.setInsertedID(m, res.InsertedID)
+	if err != nil {
 		return "", err
 	}
 
+	if err := runAfterInsert(ctx, m); err != nil {
+		return hexID, err
+	}
+
 	return hexID, nil
 }
 
-// InsertMany()
-func (s *BaseCollection) InsertMany(ctx context.Context, docs []interface{}, opts ...*options.InsertManyOptions) ([]string, error) {
+// InsertMany() inserts given documents and returns IDs of inserted documents
+// Document inputs get their timestamps and IDs like in InsertOne()
+// If some of the documents were not inserted, the method returns IDs of inserted documents and *BulkInsertError
+func (s *BaseCollection) InsertMany(ctx context.Context, docs []interface{}, opts ...*options.InsertManyOptions) (_ []string, err error) {
+	defer s.wrapError(&err, InsertManyMethod, "")
+
 	if err := s.runBeforeHooks(ctx, InsertManyMethod); err != nil {
 		return []string{}, err
 	}
 	defer s.runAfterHooks(ctx, InsertManyMethod)
 
-	res, err := s.Collection().InsertMany(ctx, docs, opts...)
+	for i := range docs {
+		if m, ok := docs[i].(Document); ok {
+			m.SetupCreatedAt()
+			m.SetupUpdatedAt()
+		}
+
+		if err := runBeforeInsert(ctx, docs[i]); err != nil {
+			return []string{}, err
+		}
+
+		if err := s.ensureDocumentKey(ctx, docs[i]); err != nil {
+			return []string{}, err
+		}
+	}
+
+	coll, err := s.ResolveCollection(ctx)
+	if err != nil {
+		return []string{}, err
+	}
+
+	scopedDocs := make([]interface{}, len(docs))
+	for i := range docs {
+		if scopedDocs[i], err = s.scopeDocument(ctx, docs[i]); err != nil {
+			return []string{}, err
+		}
+	}
 
+	res, err := coll.InsertMany(ctx, scopedDocs, opts...)
 	if err != nil {
-		return []string{}, HandleDuplicationErr(err)
+		bwErr, ok := err.(mongo.BulkWriteException)
+		if !ok || res == nil || bwErr.WriteConcernError != nil {
+			return []string{}, HandleError(err)
+		}
+
+		ordered := options.MergeInsertManyOptions(opts...).Ordered
+		return s.handleInsertManyFailures(ctx, docs, res.InsertedIDs, bwErr, ordered == nil || *ordered)
 	}
 
 	hexIDs := make([]string, len(res.InsertedIDs))
 
 	for i, insertedID := range res.InsertedIDs {
-		objectID, ok := insertedID.(primitive.ObjectID)
-
-		if !ok {
-			return hexIDs, ErrInvalidObjectID
+		hexID, err := s.setInsertedID(docs[i], insertedID)
+		if err != nil {
+			return hexIDs, err
 		}
 
-		hexIDs[i] = objectID.Hex()
+		hexIDs[i] = hexID
+	}
+
+	for i := range docs {
+		if err := runAfterInsert(ctx, docs[i]); err != nil {
+			return hexIDs, err
+		}
 	}
 
 	return hexIDs, nil
 }
 
+// handleInsertManyFailures() builds *BulkInsertError and sets IDs of successfully inserted documents
+// in ordered mode the documents after the first failed one are not inserted
+func (s *BaseCollection) handleInsertManyFailures(
+	ctx context.Context,
+	docs, insertedIDs []interface{},
+	bwErr mongo.BulkWriteException,
+	ordered bool,
+) ([]string, error) {
+	insertErr := &BulkInsertError{InsertedIDs: make(map[int]string)}
+
+	failed := make(map[int]bool)
+	firstFailed := len(docs)
+
+	for _, we := range bwErr.WriteErrors {
+		failed[we.Index] = true
+		if we.Index < firstFailed {
+			firstFailed = we.Index
+		}
+
+		insertErr.Failures = append(insertErr.Failures, newBulkOperationError(we.Index, we.WriteError))
+	}
+
+	hexIDs := []string{}
+
+	for i := range docs {
+		if failed[i] || (ordered && i > firstFailed) || i >= len(insertedIDs) {
+			continue
+		}
+
+		hexID, err := s.setInsertedID(docs[i], insertedIDs[i])
+		if err != nil {
+			return hexIDs, err
+		}
+
+		insertErr.InsertedIDs[i] = hexID
+		hexIDs = append(hexIDs, hexID)
+
+		if err := runAfterInsert(ctx, docs[i]); err != nil {
+			return hexIDs, err
+		}
+	}
+
+	return hexIDs, insertErr
+}
+
 // UpdateOne() updates given Document
-func (s *BaseCollection) UpdateOne(ctx context.Context, m Document, opts ...*options.UpdateOptions) error {
+func (s *BaseCollection) UpdateOne(ctx context.Context, m Document, opts ...*options.UpdateOptions) (err error) {
+	defer s.wrapError(&err, UpdateOneMethod, m)
+
 	if err := s.runBeforeHooks(ctx, UpdateOneMethod); err != nil {
 		return err
 	}
 	defer s.runAfterHooks(ctx, UpdateOneMethod)
 
-	filter := bson.M{CollectionIDKey: bson.M{"$eq": m.GetID()}}
-	return s.UpdateManyByFilter(ctx, filter, m, opts...)
+	filter := bson.M{CollectionIDKey: bson.M{"$eq": documentKey(m)}}
+	return s.UpdateManyByFilter(s.nested(ctx), filter, m, opts...)
 }
 
 // UpdateByFilter() updates given Document according to provided filter
-func (s *BaseCollection) UpdateManyByFilter(ctx context.Context, filter interface{}, m Document, opts ...*options.UpdateOptions) error {
+func (s *BaseCollection) UpdateManyByFilter(ctx context.Context, filter interface{}, m Document, opts ...*options.UpdateOptions) (err error) {
+	defer s.wrapError(&err, UpdateManyByFilterMethod, "")
+
 	if err := s.runBeforeHooks(ctx, UpdateManyByFilterMethod); err != nil {
 		return err
 	}
 	defer s.runAfterHooks(ctx, UpdateManyByFilterMethod)
 
+	res, err := s.updateByFilter(ctx, UpdateManyByFilterMethod, filter, m, opts...)
+	if err != nil {
+		return err
+	}
+
+	if res.Matched == 0 {
+		return ErrDocumentNotFound
+	}
+
+	if res.Modified == 0 && !s.TreatNoOpAsSuccess {
+		return ErrDocumentNotModified
+	}
+
+	return runAfterUpdate(ctx, m)
+}
+
+// updateByFilter() sets fields of the document to the documents matching the filter
+func (s *BaseCollection) updateByFilter(
+	ctx context.Context,
+	method string,
+	filter interface{},
+	m Document,
+	opts ...*options.UpdateOptions,
+) (*WriteResult, error) {
 	m.SetupUpdatedAt()
 
-	res, err := s.Collection().UpdateMany(
-		ctx,
-		filter,
-		bson.D{primitive.E{Key: "$set", Value: m}},
-		opts...,
-	)
+	if err := runBeforeUpdate(ctx, m); err != nil {
+		return nil, err
+	}
 
+	coll, filter, err := s.scope(ctx, filter)
 	if err != nil {
-		return HandleDuplicationErr(err)
+		return nil, err
 	}
 
-	if res.MatchedCount == 0 {
-		return ErrDocumentNotFound
+	doc, err := s.withoutRelations(m)
+	if err != nil {
+		return nil, err
 	}
 
-	if res.ModifiedCount == 0 {
-		return ErrDocumentNotModified
+	var res *mongo.UpdateResult
+
+	err = s.retry(ctx, method, true, func(ctx context.Context) (err error) {
+		res, err = coll.UpdateMany(
+			ctx,
+			filter,
+			bson.D{primitive.E{Key: "$set", Value: doc}},
+			opts...,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, HandleError(err)
 	}
 
-	return nil
+	reportUpdateResult(ctx, res)
+
+	return newWriteResult(res), nil
 }
 
 // UpdateMany()
@@ -291,19 +640,38 @@ func (s *BaseCollection) UpdateMany(
 	ctx context.Context,
 	filter, update interface{},
 	opts ...*options.UpdateOptions,
-) (*mongo.UpdateResult, error) {
+) (_ *mongo.UpdateResult, err error) {
+	defer s.wrapError(&err, UpdateManyMethod, "")
+
 	if err := s.runBeforeHooks(ctx, UpdateManyMethod); err != nil {
 		return nil, err
 	}
 
 	defer s.runAfterHooks(ctx, UpdateManyMethod)
 
-	return s.Collection().UpdateMany(
-		ctx,
-		filter,
-		update,
-		opts...,
-	)
+	coll, filter, err := s.scope(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err = buildUpdate(update)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *mongo.UpdateResult
+
+	err = s.retry(ctx, UpdateManyMethod, true, func(ctx context.Context) (err error) {
+		res, err = coll.UpdateMany(
+			ctx,
+			filter,
+			update,
+			opts...,
+		)
+		return err
+	})
+
+	return res, HandleError(err)
 }
 
 // FindAndUpdate - find and update existing record. Returns updated model.
@@ -312,30 +680,49 @@ func (s *BaseCollection) FindAndUpdateOne(
 	filter interface{},
 	update bson.M,
 	m Document,
-) (Document, error) {
+) (_ Document, err error) {
+	defer s.wrapError(&err, FindAndUpdateOneMethod, m)
+
 	if err := s.runBeforeHooks(ctx, FindAndUpdateOneMethod); err != nil {
 		return nil, err
 	}
 
 	defer s.runAfterHooks(ctx, FindAndUpdateOneMethod)
 
+	coll, filter, err := s.scope(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
-	res := s.Collection().FindOneAndUpdate(ctx, filter, update, opts)
-	if err := res.Decode(m); err != nil {
-		if res.Err() == mongo.ErrNoDocuments {
-			return nil, ErrDocumentNotFound
+	var b bson.Raw
+
+	err = s.retry(ctx, FindAndUpdateOneMethod, true, func(ctx context.Context) (err error) {
+		queryCtx, queryCancel := withTimeout(ctx, s.timeouts(ctx).Query)
+		defer queryCancel()
+
+		res := coll.FindOneAndUpdate(queryCtx, filter, update, opts)
+		if err = res.Decode(m); err != nil {
+			return err
 		}
 
-		return nil, err
+		b, err = res.DecodeBytes()
+		return err
+	})
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrDocumentNotFound
 	}
 
-	b, err := res.DecodeBytes()
 	if err != nil {
+		return nil, HandleError(err)
+	}
+
+	if err := setDecodedID(m, b); err != nil {
 		return nil, err
 	}
 
-	if err := m.SetJSONID(b.Lookup(CollectionIDKey).Value); err != nil {
+	if err := runAfterFind(ctx, m); err != nil {
 		return nil, err
 	}
 
@@ -348,7 +735,9 @@ func (s *BaseCollection) UpsertOne(
 	filter interface{},
 	update bson.M,
 	m Document,
-) (Document, error) {
+) (_ Document, err error) {
+	defer s.wrapError(&err, UpsertOneMethod, m)
+
 	if err := s.runBeforeHooks(ctx, UpsertOneMethod); err != nil {
 		return nil, err
 	}
@@ -359,21 +748,38 @@ func (s *BaseCollection) UpsertOne(
 		SetReturnDocument(options.After).
 		SetUpsert(true)
 
-	res := s.Collection().FindOneAndUpdate(ctx, filter, update, opts)
-	if err := res.Decode(m); err != nil {
-		if res.Err() == mongo.ErrNoDocuments {
-			return nil, ErrDocumentNotFound
+	coll, filter, err := s.scope(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bson.Raw
+
+	err = s.retry(ctx, UpsertOneMethod, true, func(ctx context.Context) (err error) {
+		queryCtx, queryCancel := withTimeout(ctx, s.timeouts(ctx).Query)
+		defer queryCancel()
+
+		res := coll.FindOneAndUpdate(queryCtx, filter, update, opts)
+		if err = res.Decode(m); err != nil {
+			return err
 		}
 
-		return nil, err
+		b, err = res.DecodeBytes()
+		return err
+	})
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrDocumentNotFound
 	}
 
-	b, err := res.DecodeBytes()
 	if err != nil {
+		return nil, HandleError(err)
+	}
+
+	if err := setDecodedID(m, b); err != nil {
 		return nil, err
 	}
 
-	if err := m.SetJSONID(b.Lookup(CollectionIDKey).Value); err != nil {
+	if err := runAfterFind(ctx, m); err != nil {
 		return nil, err
 	}
 
@@ -386,7 +792,9 @@ func (s *BaseCollection) ReplaceOne(
 	filter interface{},
 	m Document,
 	opts ...*options.ReplaceOptions,
-) (*mongo.UpdateResult, error) {
+) (_ *mongo.UpdateResult, err error) {
+	defer s.wrapError(&err, ReplaceOneMethod, m)
+
 	if err := s.runBeforeHooks(ctx, ReplaceOneMethod); err != nil {
 		return nil, err
 	}
@@ -395,12 +803,47 @@ func (s *BaseCollection) ReplaceOne(
 
 	m.SetupUpdatedAt()
 
-	return s.Collection().ReplaceOne(
-		ctx,
-		filter,
-		m,
-		opts...,
-	)
+	if err := runBeforeUpdate(ctx, m); err != nil {
+		return nil, err
+	}
+
+	coll, filter, err := s.scope(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := s.withoutRelations(m)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err = s.scopeDocument(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *mongo.UpdateResult
+
+	err = s.retry(ctx, ReplaceOneMethod, true, func(ctx context.Context) (err error) {
+		res, err = coll.ReplaceOne(
+			ctx,
+			filter,
+			doc,
+			opts...,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, HandleError(err)
+	}
+
+	if res.MatchedCount > 0 {
+		if err := runAfterUpdate(ctx, m); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
 }
 
 // ReplaceOneByID()
@@ -409,21 +852,23 @@ func (s *BaseCollection) ReplaceOneByID(
 	recordID string,
 	m Document,
 	opts ...*options.ReplaceOptions,
-) (*mongo.UpdateResult, error) {
+) (_ *mongo.UpdateResult, err error) {
+	defer s.wrapError(&err, ReplaceOneByIDMethod, recordID)
+
 	if err := s.runBeforeHooks(ctx, ReplaceOneByIDMethod); err != nil {
 		return nil, err
 	}
 
 	defer s.runAfterHooks(ctx, ReplaceOneByIDMethod)
 
-	oid, err := primitive.ObjectIDFromHex(recordID)
+	key, err := s.idStrategy().Parse(recordID)
 	if err != nil {
-		return nil, ErrInvalidObjectID
+		return nil, err
 	}
 
 	return s.ReplaceOne(
-		ctx,
-		bson.M{CollectionIDKey: bson.M{"$eq": oid}},
+		s.nested(ctx),
+		bson.M{CollectionIDKey: bson.M{"$eq": key}},
 		m,
 		opts...,
 	)
@@ -435,21 +880,23 @@ func (s *BaseCollection) GetOneByID(
 	recordID string,
 	m Document,
 	opts ...*options.FindOneOptions,
-) error {
+) (err error) {
+	defer s.wrapError(&err, GetOneByIDMethod, recordID)
+
 	if err := s.runBeforeHooks(ctx, GetOneByIDMethod); err != nil {
 		return err
 	}
 
 	defer s.runAfterHooks(ctx, GetOneByIDMethod)
 
-	oid, err := primitive.ObjectIDFromHex(recordID)
+	key, err := s.idStrategy().Parse(recordID)
 	if err != nil {
-		return ErrInvalidObjectID
+		return err
 	}
 
-	filter := bson.M{CollectionIDKey: oid}
+	filter := bson.M{CollectionIDKey: key}
 
-	return s.GetOneByFilter(ctx, filter, m, opts...)
+	return s.GetOneByFilter(s.nested(ctx), filter, m, opts...)
 }
 
 // GetOneByFilter() is trying to find Document by provided filter
@@ -458,33 +905,55 @@ func (s *BaseCollection) GetOneByFilter(
 	filter interface{},
 	m Document,
 	opts ...*options.FindOneOptions,
-) error {
+) (err error) {
+	defer s.wrapError(&err, GetOneByFilterMethod, "")
+
 	if err := s.runBeforeHooks(ctx, GetOneByFilterMethod); err != nil {
 		return err
 	}
 
 	defer s.runAfterHooks(ctx, GetOneByFilterMethod)
 
-	res := s.Collection().FindOne(ctx, filter, opts...)
-	if err := res.Decode(m); err != nil {
-		if res.Err() == mongo.ErrNoDocuments {
-			return ErrDocumentNotFound
+	coll, filter, err := s.scope(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	var b bson.Raw
+
+	err = s.retry(ctx, GetOneByFilterMethod, false, func(ctx context.Context) (err error) {
+		queryCtx, queryCancel := withTimeout(ctx, s.timeouts(ctx).Query)
+		defer queryCancel()
+
+		res := coll.FindOne(queryCtx, filter, opts...)
+		if err = res.Decode(m); err != nil {
+			return err
 		}
 
+		b, err = res.DecodeBytes()
 		return err
+	})
+	if err == mongo.ErrNoDocuments {
+		return ErrDocumentNotFound
 	}
 
-	b, err := res.DecodeBytes()
-
 	if err != nil {
+		return HandleError(err)
+	}
+
+	if err := setDecodedID(m, b); err != nil {
 		return err
 	}
 
-	if err := m.SetJSONID(b.Lookup(CollectionIDKey).Value); err != nil {
+	if err := s.populate(ctx, []bson.Raw{b}, []interface{}{m}); err != nil {
 		return err
 	}
 
-	return nil
+	if err := runAfterFind(ctx, m); err != nil {
+		return err
+	}
+
+	return trackChanges(s.BSONRegistry(), m)
 }
 
 // GetManyByFilter()
@@ -493,28 +962,38 @@ func (s *BaseCollection) GetManyByFilter(
 	filter interface{},
 	modelBuilder func() Document,
 	opts ...*options.FindOptions,
-) ([]Document, error) {
+) (_ []Document, err error) {
+	defer s.wrapError(&err, GetManyByFilterMethod, "")
+
 	if err := s.runBeforeHooks(ctx, GetManyByFilterMethod); err != nil {
 		return nil, err
 	}
 
 	defer s.runAfterHooks(ctx, GetManyByFilterMethod)
 
-	filterCtx, filterCancel := context.WithTimeout(ctx, FilterTimeout)
+	timeouts := s.timeouts(ctx)
+
+	filterCtx, filterCancel := withTimeout(ctx, timeouts.Filter)
 	defer filterCancel()
 
-	cur, err := s.FindManyByFilter(filterCtx, filter, opts...)
+	cur, err := s.FindManyByFilter(s.nested(filterCtx), filter, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	closeCtx, closeCancel := context.WithTimeout(ctx, CloseCursorTimeout)
+	closeCtx, closeCancel := withTimeout(ctx, timeouts.CloseCursor)
 	defer closeCancel()
 	defer cur.Close(closeCtx)
 
-	var l []Document
+	var (
+		l    []Document
+		docs []interface{}
+		raws []bson.Raw
+	)
+
+	populate := len(PopulateFromContext(ctx)) > 0
 
-	nextCtx, nextCancel := context.WithTimeout(context.Background(), FetchTimeout)
+	nextCtx, nextCancel := withTimeout(ctx, timeouts.Fetch)
 
 	defer nextCancel()
 
@@ -524,11 +1003,31 @@ func (s *BaseCollection) GetManyByFilter(
 			return nil, err
 		}
 
-		if err := m.SetJSONID(cur.Current.Lookup(CollectionIDKey).Value); err != nil {
+		if err := setDecodedID(m, cur.Current); err != nil {
 			return nil, err
 		}
 
 		l = append(l, m)
+
+		if populate {
+			// the cursor reuses the buffer of the current document
+			raws = append(raws, append(bson.Raw(nil), cur.Current...))
+			docs = append(docs, m)
+		}
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, HandleError(err)
+	}
+
+	if err := s.populate(ctx, raws, docs); err != nil {
+		return nil, err
+	}
+
+	for _, m := range l {
+		if err := runAfterFind(ctx, m); err != nil {
+			return nil, err
+		}
 	}
 
 	return l, nil
@@ -540,29 +1039,37 @@ func (s *BaseCollection) FindAllByFilter(
 	filter interface{},
 	docs interface{},
 	opts ...*options.FindOptions,
-) error {
+) (err error) {
+	defer s.wrapError(&err, FindAllByFilterMethod, "")
+
 	if err := s.runBeforeHooks(ctx, FindAllByFilterMethod); err != nil {
 		return err
 	}
 
 	defer s.runAfterHooks(ctx, FindAllByFilterMethod)
 
-	filterCtx, filterCancel := context.WithTimeout(ctx, FilterTimeout)
+	timeouts := s.timeouts(ctx)
+
+	filterCtx, filterCancel := withTimeout(ctx, timeouts.Filter)
 	defer filterCancel()
 
-	cur, err := s.FindManyByFilter(filterCtx, filter, opts...)
+	cur, err := s.FindManyByFilter(s.nested(filterCtx), filter, opts...)
 	if err != nil {
 		return err
 	}
 
-	closeCtx, closeCancel := context.WithTimeout(ctx, CloseCursorTimeout)
+	closeCtx, closeCancel := withTimeout(ctx, timeouts.CloseCursor)
 	defer closeCancel()
 	defer cur.Close(closeCtx)
 
-	allCtx, allCancel := context.WithTimeout(context.Background(), FetchTimeout)
+	allCtx, allCancel := withTimeout(ctx, timeouts.Fetch)
 	defer allCancel()
 
-	return cur.All(allCtx, docs)
+	if err := cur.All(allCtx, docs); err != nil {
+		return HandleError(err)
+	}
+
+	return runAfterFindAll(ctx, docs)
 }
 
 // FindManyByFilter()
@@ -570,40 +1077,73 @@ func (s *BaseCollection) FindManyByFilter(
 	ctx context.Context,
 	filter interface{},
 	opts ...*options.FindOptions,
-) (*mongo.Cursor, error) {
+) (_ *mongo.Cursor, err error) {
+	defer s.wrapError(&err, FindManyByFilterMethod, "")
+
 	if err := s.runBeforeHooks(ctx, FindManyByFilterMethod); err != nil {
 		return nil, err
 	}
 
 	defer s.runAfterHooks(ctx, FindManyByFilterMethod)
 
-	cur, err := s.Collection().Find(ctx, filter, opts...)
+	coll, filter, err := s.scope(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
+	var cur *mongo.Cursor
+
+	err = s.retry(ctx, FindManyByFilterMethod, false, func(ctx context.Context) (err error) {
+		cur, err = coll.Find(ctx, filter, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, HandleError(err)
+	}
+
 	if cur.Err() == nil {
 		return cur, nil
 	}
 
-	closeCtx, closeCancel := context.WithTimeout(ctx, CloseCursorTimeout)
+	closeCtx, closeCancel := withTimeout(ctx, s.timeouts(ctx).CloseCursor)
 	defer closeCancel()
 	defer cur.Close(closeCtx)
 
-	return nil, cur.Err()
+	return nil, HandleError(cur.Err())
 }
 
 // CountByFilter
 func (s *BaseCollection) CountByFilter(
 	ctx context.Context,
 	filter interface{},
-) (int64, error) {
-	opts := options.Count().SetMaxTime(2 * time.Second)
-	return s.Collection().CountDocuments(
-		context.TODO(),
-		filter,
-		opts,
-	)
+) (_ int64, err error) {
+	defer s.wrapError(&err, CountByFilterMethod, "")
+
+	if err := s.runBeforeHooks(ctx, CountByFilterMethod); err != nil {
+		return 0, err
+	}
+
+	defer s.runAfterHooks(ctx, CountByFilterMethod)
+
+	coll, filter, err := s.scope(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+
+	err = s.retry(ctx, CountByFilterMethod, false, func(ctx context.Context) (err error) {
+		countCtx, countCancel := withTimeout(ctx, s.timeouts(ctx).Count)
+		defer countCancel()
+
+		count, err = coll.CountDocuments(
+			countCtx,
+			filter,
+		)
+		return err
+	})
+
+	return count, HandleError(err)
 }
 
 // DeleteManyByFilter() documents by given filters
@@ -611,45 +1151,108 @@ func (s *BaseCollection) DeleteManyByFilter(
 	ctx context.Context,
 	filter interface{},
 	opts ...*options.DeleteOptions,
-) (*mongo.DeleteResult, error) {
+) (_ *mongo.DeleteResult, err error) {
+	defer s.wrapError(&err, DeleteManyByFilterMethod, "")
+
 	if err := s.runBeforeHooks(ctx, DeleteManyByFilterMethod); err != nil {
 		return nil, err
 	}
 	defer s.runAfterHooks(ctx, DeleteManyByFilterMethod)
 
-	return s.Collection().DeleteMany(ctx, filter, opts...)
+	coll, filter, err := s.scope(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *mongo.DeleteResult
+
+	err = s.retry(ctx, DeleteManyByFilterMethod, true, func(ctx context.Context) (err error) {
+		res, err = coll.DeleteMany(ctx, filter, opts...)
+		return err
+	})
+
+	return res, HandleError(err)
 }
 
 // DeleteOneByID() deletes document by given ID
-func (s *BaseCollection) DeleteOneByID(ctx context.Context, docID string) error {
+func (s *BaseCollection) DeleteOneByID(ctx context.Context, docID string) (err error) {
+	defer s.wrapError(&err, DeleteOneByIDMethod, docID)
+
 	if err := s.runBeforeHooks(ctx, DeleteOneByIDMethod); err != nil {
 		return err
 	}
 
 	defer s.runAfterHooks(ctx, DeleteOneByIDMethod)
 
-	oid, err := primitive.ObjectIDFromHex(docID)
+	key, err := s.idStrategy().Parse(docID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{CollectionIDKey: key}
+
+	r, err := s.DeleteManyByFilter(s.nested(ctx), filter)
 	if err != nil {
-		return ErrInvalidObjectID
+		return err
+	}
+
+	if r.DeletedCount != 1 {
+		return ErrDocumentNotFound
 	}
 
-	filter := bson.M{CollectionIDKey: oid}
+	return nil
+}
+
+// DeleteOne() deletes given Document and runs its delete callbacks
+func (s *BaseCollection) DeleteOne(ctx context.Context, m Document) (err error) {
+	defer s.wrapError(&err, DeleteOneMethod, m)
+
+	if err := s.runBeforeHooks(ctx, DeleteOneMethod); err != nil {
+		return err
+	}
+
+	defer s.runAfterHooks(ctx, DeleteOneMethod)
+
+	if err := runBeforeDelete(ctx, m); err != nil {
+		return err
+	}
+
+	filter := bson.M{CollectionIDKey: documentKey(m)}
+
+	r, err := s.DeleteManyByFilter(s.nested(ctx), filter)
+	if err != nil {
+		return err
+	}
 
-	r, err := s.DeleteManyByFilter(ctx, filter)
 	if r.DeletedCount != 1 {
 		return ErrDocumentNotFound
 	}
 
-	return err
+	return runAfterDelete(ctx, m)
 }
 
 // DropAll() deletes collection from database
-func (s *BaseCollection) DeleteAll(ctx context.Context) error {
+// Only documents matching default scopes and the tenant are deleted from scoped collections,
+// use Unscoped().DeleteAll() to delete all of them
+func (s *BaseCollection) DeleteAll(ctx context.Context) (err error) {
+	defer s.wrapError(&err, DeleteAllMethod, "")
+
 	if err := s.runBeforeHooks(ctx, DeleteAllMethod); err != nil {
 		return err
 	}
 
 	defer s.runAfterHooks(ctx, DeleteAllMethod)
 
-	return s.Collection().Drop(ctx)
+	coll, filter, err := s.scope(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// scoped collections and tenants in TenantPerField mode delete only matching documents
+	if filter != nil {
+		_, err = coll.DeleteMany(ctx, filter)
+		return HandleError(err)
+	}
+
+	return HandleError(coll.Drop(ctx))
 }
@@ -344,7 +344,7 @@ var _ = Describe("BaseCollection", func() {
 
 						updateErr := storage.UpdateManyByFilter(context.TODO(), filter, update)
 
-						Expect(updateErr).To(Equal(ErrDocumentNotModified))
+						Expect(errors.Is(updateErr, ErrDocumentNotModified)).To(BeTrue())
 
 					})
 				})
@@ -420,7 +420,7 @@ var _ = Describe("BaseCollection", func() {
 
 							_, updateErr := storage.ReplaceOneByID(context.TODO(), "123", m)
 
-							Expect(updateErr).To(Equal(ErrInvalidObjectID))
+							Expect(errors.Is(updateErr, ErrInvalidObjectID)).To(BeTrue())
 						})
 					})
 
@@ -476,7 +476,7 @@ var _ = Describe("BaseCollection", func() {
 						emptyModel := &ExampleModel{}
 						findErr := storage.GetOneByID(context.TODO(), id, emptyModel)
 
-						Expect(findErr).To(Equal(ErrInvalidObjectID))
+						Expect(errors.Is(findErr, ErrInvalidObjectID)).To(BeTrue())
 					})
 				})
 
@@ -499,7 +499,7 @@ var _ = Describe("BaseCollection", func() {
 						findErr := storage.GetOneByID(context.TODO(), id, emptyModel)
 
 						Expect(updateErr).NotTo(BeNil())
-						Expect(findErr).To(Equal(ErrInvalidObjectID))
+						Expect(errors.Is(findErr, ErrInvalidObjectID)).To(BeTrue())
 					})
 				})
 			})
@@ -566,6 +566,63 @@ var _ = Describe("BaseCollection", func() {
 					Expect(emptyModel.UpdatedAt.Unix()).To(Equal(curTime.Unix()))
 				}
 			})
+
+			It("should set ids and timestamps of inserted documents", func() {
+				curTime := time.Now().UTC().Add(time.Hour * 1)
+
+				timecop.Freeze(curTime)
+				defer timecop.Return()
+
+				m := NewExampleModel()
+
+				ids, err := storage.InsertMany(context.TODO(), []interface{}{m})
+
+				Expect(err).To(BeNil())
+				Expect(ids).To(Equal([]string{m.GetHexID()}))
+				Expect(m.CreatedAt).To(Equal(curTime))
+				Expect(m.UpdatedAt).To(Equal(curTime))
+			})
+
+			Context("with duplicated documents", func() {
+				var (
+					existing *ExampleModel
+				)
+
+				BeforeEach(func() {
+					existing = NewExampleModel()
+					storage.InsertOne(context.TODO(), existing)
+				})
+
+				It("should return ids of inserted documents in unordered mode", func() {
+					m1 := NewExampleModel()
+					m2 := NewExampleModel()
+					docs := []interface{}{m1, existing, m2}
+
+					ids, err := storage.InsertMany(context.TODO(), docs, options.InsertMany().SetOrdered(false))
+
+					insertErr := &BulkInsertError{}
+					Expect(errors.As(err, &insertErr)).To(BeTrue())
+					Expect(ids).To(Equal([]string{m1.GetHexID(), m2.GetHexID()}))
+					Expect(insertErr.InsertedIDs).To(Equal(map[int]string{0: m1.GetHexID(), 2: m2.GetHexID()}))
+					Expect(len(insertErr.Failures)).To(Equal(1))
+					Expect(insertErr.Failures[0].Index).To(Equal(1))
+					Expect(insertErr.Failures[0].Code).To(Equal(DuplicationErrorCode))
+					Expect(errors.Is(insertErr.Failures[0].Err, ErrDocumentDuplication)).To(BeTrue())
+				})
+
+				It("should stop at the first failed document in ordered mode", func() {
+					m1 := NewExampleModel()
+					m2 := NewExampleModel()
+					docs := []interface{}{m1, existing, m2}
+
+					ids, err := storage.InsertMany(context.TODO(), docs)
+
+					insertErr := &BulkInsertError{}
+					Expect(errors.As(err, &insertErr)).To(BeTrue())
+					Expect(ids).To(Equal([]string{m1.GetHexID()}))
+					Expect(len(insertErr.Failures)).To(Equal(1))
+				})
+			})
 		})
 
 		Describe("UpdateMany()", func() {
@@ -941,7 +998,7 @@ var _ = Describe("BaseCollection", func() {
 
 				delErr2 := storage.DeleteOneByID(context.TODO(), m1.GetHexID())
 
-				Expect(delErr2).To(Equal(ErrDocumentNotFound))
+				Expect(errors.Is(delErr2, ErrDocumentNotFound)).To(BeTrue())
 			})
 		})
 
@@ -1126,7 +1183,7 @@ var _ = Describe("BaseCollection", func() {
 
 				/* update existing record */
 				_, err := storage.FindAndUpdateOne(ctx, filter, update, &ExampleModel{})
-				Expect(err).To(Equal(ErrDocumentNotFound))
+				Expect(errors.Is(err, ErrDocumentNotFound)).To(BeTrue())
 			})
 		})
 	})
@@ -0,0 +1,385 @@
+package mongol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// DefaultBulkChunkSize is a maximum number of operations sent in one bulk write request
+	DefaultBulkChunkSize = 1000
+)
+
+const (
+	bulkInsert = iota
+	bulkUpdate
+	bulkReplace
+	bulkDelete
+)
+
+type bulkOperation struct {
+	kind  int
+	model mongo.WriteModel
+	doc   interface{}
+}
+
+// BulkWriteBuilder collects mixed insert, update, replace and delete operations for BulkWrite()
+type BulkWriteBuilder struct {
+	operations []bulkOperation
+	ordered    bool
+	chunkSize  int
+}
+
+// NewBulkWriteBuilder() initializes a new ordered BulkWriteBuilder
+func NewBulkWriteBuilder() *BulkWriteBuilder {
+	return &BulkWriteBuilder{
+		ordered:   true,
+		chunkSize: DefaultBulkChunkSize,
+	}
+}
+
+// Ordered() sets the execution mode, in ordered mode the execution stops at the first failed operation
+func (b *BulkWriteBuilder) Ordered(ordered bool) *BulkWriteBuilder {
+	b.ordered = ordered
+
+	return b
+}
+
+// ChunkSize() sets a maximum number of operations sent in one request
+func (b *BulkWriteBuilder) ChunkSize(size int) *BulkWriteBuilder {
+	if size < 1 {
+		size = DefaultBulkChunkSize
+	}
+
+	b.chunkSize = size
+
+	return b
+}
+
+// Len() returns the number of collected operations
+func (b *BulkWriteBuilder) Len() int {
+	return len(b.operations)
+}
+
+// InsertOne() adds insert operation, Document gets its timestamps and ID before the execution
+func (b *BulkWriteBuilder) InsertOne(doc interface{}) *BulkWriteBuilder {
+	return b.add(bulkInsert, mongo.NewInsertOneModel().SetDocument(doc), doc)
+}
+
+// UpdateOne() adds update operation for the first document matching the filter
+func (b *BulkWriteBuilder) UpdateOne(filter, update interface{}) *BulkWriteBuilder {
+	return b.add(bulkUpdate, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update), nil)
+}
+
+// UpsertOne() adds update operation which inserts the document if nothing matches the filter
+func (b *BulkWriteBuilder) UpsertOne(filter, update interface{}) *BulkWriteBuilder {
+	return b.add(bulkUpdate, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true), nil)
+}
+
+// UpdateMany() adds update operation for all documents matching the filter
+func (b *BulkWriteBuilder) UpdateMany(filter, update interface{}) *BulkWriteBuilder {
+	return b.add(bulkUpdate, mongo.NewUpdateManyModel().SetFilter(filter).SetUpdate(update), nil)
+}
+
+// ReplaceOne() adds replace operation, Document gets its UpdatedAt before the execution
+func (b *BulkWriteBuilder) ReplaceOne(filter, doc interface{}) *BulkWriteBuilder {
+	return b.add(bulkReplace, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(doc), doc)
+}
+
+// DeleteOne() adds delete operation for the first document matching the filter
+func (b *BulkWriteBuilder) DeleteOne(filter interface{}) *BulkWriteBuilder {
+	return b.add(bulkDelete, mongo.NewDeleteOneModel().SetFilter(filter), nil)
+}
+
+// DeleteMany() adds delete operation for all documents matching the filter
+func (b *BulkWriteBuilder) DeleteMany(filter interface{}) *BulkWriteBuilder {
+	return b.add(bulkDelete, mongo.NewDeleteManyModel().SetFilter(filter), nil)
+}
+
+// Add() adds custom mongo.WriteModel, documents of insert and replace models
+// are prepared like documents of InsertOne() and ReplaceOne()
+func (b *BulkWriteBuilder) Add(model mongo.WriteModel) *BulkWriteBuilder {
+	switch m := model.(type) {
+	case *mongo.InsertOneModel:
+		return b.add(bulkInsert, m, m.Document)
+	case *mongo.ReplaceOneModel:
+		return b.add(bulkReplace, m, m.Replacement)
+	case *mongo.DeleteOneModel, *mongo.DeleteManyModel:
+		return b.add(bulkDelete, m, nil)
+	default:
+		return b.add(bulkUpdate, m, nil)
+	}
+}
+
+func (b *BulkWriteBuilder) add(kind int, model mongo.WriteModel, doc interface{}) *BulkWriteBuilder {
+	b.operations = append(b.operations, bulkOperation{kind: kind, model: model, doc: doc})
+
+	return b
+}
+
+// BulkOperationError describes a failed operation of the bulk write
+type BulkOperationError struct {
+	// Index of the operation in the builder
+	Index int
+	// Code of the MongoDB write error
+	Code int
+	Err  error
+}
+
+func (e BulkOperationError) Error() string {
+	return fmt.Sprintf("operation %d failed with code %d: %s", e.Index, e.Code, e.Err)
+}
+
+func (e BulkOperationError) Unwrap() error {
+	return e.Err
+}
+
+// BulkWriteError is returned by BulkWrite() when some of the operations failed
+type BulkWriteError struct {
+	Errors []BulkOperationError
+}
+
+func (e *BulkWriteError) Error() string {
+	return fmt.Sprintf("bulk write failed for %d operations", len(e.Errors))
+}
+
+// Unwrap() returns the error of the first failed operation, use Is() to match errors of all operations
+func (e *BulkWriteError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e.Errors[0]
+}
+
+// Is() matches errors of all failed operations, e.g. errors.Is(err, ErrDocumentDuplication)
+func (e *BulkWriteError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BulkInsertError is returned by InsertMany() when some of the documents were not inserted
+type BulkInsertError struct {
+	// InsertedIDs maps indices of inserted documents to their IDs
+	InsertedIDs map[int]string
+	// Failures of the documents which were not inserted
+	Failures []BulkOperationError
+}
+
+func (e *BulkInsertError) Error() string {
+	return fmt.Sprintf("%d documents were inserted, %d documents failed", len(e.InsertedIDs), len(e.Failures))
+}
+
+// BulkWriteResult
+type BulkWriteResult struct {
+	InsertedCount int64
+	MatchedCount  int64
+	ModifiedCount int64
+	DeletedCount  int64
+	UpsertedCount int64
+	// InsertedIDs maps indices of successful insert operations to the IDs of inserted documents
+	InsertedIDs map[int]string
+	// UpsertedIDs maps indices of upsert operations to the IDs of upserted documents
+	UpsertedIDs map[int]interface{}
+	// Errors of the failed operations
+	Errors []BulkOperationError
+}
+
+func newBulkOperationError(index int, we mongo.WriteError) BulkOperationError {
+	return BulkOperationError{Index: index, Code: we.Code, Err: HandleWriteError(we)}
+}
+
+// BulkWrite() executes collected operations in chunks
+// Per-operation errors are returned in the result and as *BulkWriteError
+// AfterInsert and AfterUpdate callbacks are run for documents of succeeded insert and replace operations
+func (s *BaseCollection) BulkWrite(ctx context.Context, b *BulkWriteBuilder) (_ *BulkWriteResult, err error) {
+	defer s.wrapError(&err, BulkWriteMethod, "")
+
+	if err := s.runBeforeHooks(ctx, BulkWriteMethod); err != nil {
+		return nil, err
+	}
+
+	defer s.runAfterHooks(ctx, BulkWriteMethod)
+
+	res := &BulkWriteResult{
+		InsertedIDs: make(map[int]string),
+		UpsertedIDs: make(map[int]interface{}),
+	}
+
+	if err := s.prepareBulkOperations(ctx, b.operations); err != nil {
+		return res, err
+	}
+
+	opts := options.BulkWrite().SetOrdered(b.ordered)
+
+	for start := 0; start < len(b.operations); start += b.chunkSize {
+		end := start + b.chunkSize
+		if end > len(b.operations) {
+			end = len(b.operations)
+		}
+
+		stop, err := s.bulkWriteChunk(ctx, b.operations[start:end], start, b.ordered, opts, res)
+		if err != nil {
+			return res, err
+		}
+
+		if stop {
+			break
+		}
+	}
+
+	if len(res.Errors) > 0 {
+		return res, &BulkWriteError{Errors: res.Errors}
+	}
+
+	return res, nil
+}
+
+// prepareBulkOperations() sets timestamps and IDs of documents
+// IDs are generated on the client side to be reported in BulkWriteResult,
+// fields of related documents are dropped from replacements
+func (s *BaseCollection) prepareBulkOperations(ctx context.Context, ops []bulkOperation) error {
+	for i := range ops {
+		m, ok := ops[i].doc.(Document)
+		if !ok {
+			continue
+		}
+
+		switch ops[i].kind {
+		case bulkInsert:
+			m.SetupCreatedAt()
+			m.SetupUpdatedAt()
+
+			if err := runBeforeInsert(ctx, m); err != nil {
+				return err
+			}
+
+			if err := s.ensureDocumentKey(ctx, m); err != nil {
+				return err
+			}
+
+			if _, keyed := m.(KeyedDocument); !keyed && m.GetID().IsZero() {
+				if err := m.SetHexID(primitive.NewObjectID().Hex()); err != nil {
+					return err
+				}
+			}
+		case bulkReplace:
+			m.SetupUpdatedAt()
+
+			if err := runBeforeUpdate(ctx, m); err != nil {
+				return err
+			}
+
+			doc, err := s.withoutRelations(m)
+			if err != nil {
+				return err
+			}
+
+			replace := *ops[i].model.(*mongo.ReplaceOneModel)
+			replace.Replacement = doc
+			ops[i].model = &replace
+		}
+	}
+
+	return nil
+}
+
+func (s *BaseCollection) bulkWriteChunk(
+	ctx context.Context,
+	ops []bulkOperation,
+	offset int,
+	ordered bool,
+	opts *options.BulkWriteOptions,
+	res *BulkWriteResult,
+) (bool, error) {
+	coll, err := s.ResolveCollection(ctx)
+	if err != nil {
+		return true, err
+	}
+
+	models := make([]mongo.WriteModel, len(ops))
+	for i := range ops {
+		if models[i], err = s.scopeWriteModel(ctx, ops[i].model); err != nil {
+			return true, err
+		}
+	}
+
+	chunkRes, err := coll.BulkWrite(ctx, models, opts)
+
+	failed := make(map[int]bool)
+	firstFailed := len(ops)
+
+	if err != nil {
+		bwErr, ok := err.(mongo.BulkWriteException)
+		if !ok || bwErr.WriteConcernError != nil {
+			return true, HandleError(err)
+		}
+
+		for _, we := range bwErr.WriteErrors {
+			failed[we.Index] = true
+			if we.Index < firstFailed {
+				firstFailed = we.Index
+			}
+
+			res.Errors = append(res.Errors, newBulkOperationError(offset+we.Index, we.WriteError))
+		}
+	}
+
+	if chunkRes != nil {
+		res.InsertedCount += chunkRes.InsertedCount
+		res.MatchedCount += chunkRes.MatchedCount
+		res.ModifiedCount += chunkRes.ModifiedCount
+		res.DeletedCount += chunkRes.DeletedCount
+		res.UpsertedCount += chunkRes.UpsertedCount
+
+		for i, id := range chunkRes.UpsertedIDs {
+			res.UpsertedIDs[offset+int(i)] = id
+		}
+	}
+
+	for i := range ops {
+		if failed[i] || (ordered && i > firstFailed) {
+			continue
+		}
+
+		m, ok := ops[i].doc.(Document)
+		if !ok {
+			continue
+		}
+
+		switch ops[i].kind {
+		case bulkInsert:
+			id, err := s.idStrategy().Format(documentKey(m))
+			if err != nil {
+				return true, err
+			}
+
+			res.InsertedIDs[offset+i] = id
+
+			if err := runAfterInsert(ctx, m); err != nil {
+				return true, err
+			}
+		case bulkReplace:
+			// matches are reported per chunk, so a replace is known to be a no-op only in a chunk without matches
+			if chunkRes == nil || chunkRes.MatchedCount+chunkRes.UpsertedCount == 0 {
+				continue
+			}
+
+			if err := runAfterUpdate(ctx, m); err != nil {
+				return true, err
+			}
+		}
+	}
+
+	return ordered && len(failed) > 0, nil
+}
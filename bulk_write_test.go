@@ -0,0 +1,242 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	. "github.com/wajox/mongol"
+)
+
+// nolint
+var _ = Describe("BulkWrite", func() {
+	var (
+		storage *BaseCollection
+	)
+
+	BeforeEach(func() {
+		storage = newTestCollection("bulk_write_test")
+	})
+
+	Describe("NewBulkWriteBuilder()", func() {
+		It("should collect operations", func() {
+			b := NewBulkWriteBuilder().
+				InsertOne(NewExampleModel()).
+				UpdateOne(bson.M{"title": "a"}, bson.M{"$set": bson.M{"title": "b"}}).
+				DeleteMany(bson.M{"title": "b"})
+
+			Expect(b.Len()).To(Equal(3))
+		})
+	})
+
+	Describe("BulkOperationError", func() {
+		It("should unwrap the original error", func() {
+			err := BulkOperationError{Index: 1, Code: DuplicationErrorCode, Err: ErrDocumentDuplication}
+
+			Expect(errors.Is(err, ErrDocumentDuplication)).To(BeTrue())
+		})
+	})
+
+	Describe("BulkWriteError", func() {
+		It("should match errors of all operations", func() {
+			err := error(&BulkWriteError{Errors: []BulkOperationError{
+				{Index: 0, Err: ErrDocumentNotModified},
+				{Index: 2, Code: DuplicationErrorCode, Err: ErrDocumentDuplication},
+			}})
+
+			Expect(errors.Is(err, ErrDocumentDuplication)).To(BeTrue())
+			Expect(errors.Is(err, ErrDocumentNotModified)).To(BeTrue())
+			Expect(errors.Is(err, ErrDocumentNotFound)).To(BeFalse())
+
+			var opErr BulkOperationError
+			Expect(errors.As(err, &opErr)).To(BeTrue())
+			Expect(opErr.Index).To(Equal(0))
+		})
+	})
+
+	Describe("BulkWriteBuilder.Add()", func() {
+		It("should prepare documents of insert and replace models", func() {
+			inserted := &CallbackModel{Title: "Inserted", FailOn: "BeforeInsert"}
+
+			_, err := storage.BulkWrite(context.TODO(), NewBulkWriteBuilder().Add(mongo.NewInsertOneModel().SetDocument(inserted)))
+			Expect(errors.Is(err, errCallback)).To(BeTrue())
+			Expect(inserted.Calls).To(Equal([]string{"BeforeInsert"}))
+			Expect(inserted.CreatedAt).NotTo(BeZero())
+
+			replaced := &CallbackModel{Title: "Replaced", FailOn: "BeforeUpdate"}
+
+			_, err = storage.BulkWrite(context.TODO(), NewBulkWriteBuilder().
+				Add(mongo.NewDeleteOneModel().SetFilter(bson.M{})).
+				Add(mongo.NewReplaceOneModel().SetFilter(bson.M{}).SetReplacement(replaced)))
+			Expect(errors.Is(err, errCallback)).To(BeTrue())
+			Expect(replaced.Calls).To(Equal([]string{"BeforeUpdate"}))
+		})
+	})
+
+	Context("with unresolved builders", func() {
+		It("should return the error of field paths resolution", func() {
+			resolver := NewFieldResolver(&ResolvedModel{})
+
+			_, err := storage.BulkWrite(context.TODO(), NewBulkWriteBuilder().
+				DeleteMany(NewFilterBuilder().WithResolver(resolver).EqualTo("Titel", "a")))
+			Expect(errors.Is(err, ErrUnknownField)).To(BeTrue())
+
+			_, err = storage.BulkWrite(context.TODO(), NewBulkWriteBuilder().
+				UpdateMany(bson.M{}, NewUpdateBuilder().WithResolver(resolver).Set("Titel", "a")))
+			Expect(errors.Is(err, ErrUnknownField)).To(BeTrue())
+		})
+	})
+
+	Context("without operations", func() {
+		It("should return an empty result", func() {
+			res, err := storage.BulkWrite(context.TODO(), NewBulkWriteBuilder())
+
+			Expect(err).To(BeNil())
+			Expect(res.InsertedCount).To(Equal(int64(0)))
+		})
+	})
+
+	Context("with failing hook", func() {
+		It("should return error", func() {
+			storage.AddBeforeHook(BulkWriteMethod, func(context.Context) error {
+				return errors.New("some error")
+			})
+
+			_, err := storage.BulkWrite(context.TODO(), NewBulkWriteBuilder().InsertOne(NewExampleModel()))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("methods", func() {
+		AfterEach(func() {
+			storage.DeleteAll(context.TODO())
+		})
+
+		It("should execute mixed operations in chunks", func() {
+			m1 := NewExampleModel()
+			m2 := NewExampleModel()
+			m3 := NewExampleModel()
+
+			b := NewBulkWriteBuilder().
+				ChunkSize(2).
+				InsertOne(m1).
+				InsertOne(m2).
+				InsertOne(m3).
+				UpdateOne(bson.M{"title": m1.Title}, bson.M{"$set": bson.M{"title": "updated"}}).
+				UpsertOne(bson.M{"title": "upserted"}, bson.M{"$set": bson.M{"title": "upserted"}}).
+				DeleteOne(bson.M{"title": m2.Title})
+
+			res, err := storage.BulkWrite(context.TODO(), b)
+
+			Expect(err).To(BeNil())
+			Expect(res.InsertedCount).To(Equal(int64(3)))
+			Expect(res.ModifiedCount).To(Equal(int64(1)))
+			Expect(res.UpsertedCount).To(Equal(int64(1)))
+			Expect(res.DeletedCount).To(Equal(int64(1)))
+			Expect(res.InsertedIDs).To(Equal(map[int]string{0: m1.GetHexID(), 1: m2.GetHexID(), 2: m3.GetHexID()}))
+			Expect(res.UpsertedIDs).To(HaveKey(4))
+
+			count, _ := storage.CountByFilter(context.TODO(), bson.M{})
+			Expect(count).To(Equal(int64(3)))
+		})
+
+		It("should report duplicates by input index in unordered mode", func() {
+			m := NewExampleModel()
+			_, err := storage.InsertOne(context.TODO(), m)
+			Expect(err).To(BeNil())
+
+			other := NewExampleModel()
+			b := NewBulkWriteBuilder().
+				Ordered(false).
+				ChunkSize(1).
+				InsertOne(m).
+				InsertOne(other).
+				InsertOne(m)
+
+			res, err := storage.BulkWrite(context.TODO(), b)
+
+			bwErr := &BulkWriteError{}
+			Expect(errors.As(err, &bwErr)).To(BeTrue())
+			Expect(len(res.Errors)).To(Equal(2))
+			Expect(res.Errors[0].Index).To(Equal(0))
+			Expect(res.Errors[1].Index).To(Equal(2))
+			Expect(errors.Is(res.Errors[0].Err, ErrDocumentDuplication)).To(BeTrue())
+			Expect(errors.Is(err, ErrDocumentDuplication)).To(BeTrue())
+			Expect(res.InsertedIDs).To(Equal(map[int]string{1: other.GetHexID()}))
+		})
+
+		It("should resolve filter and update builders", func() {
+			kept := NewExampleModel()
+			kept.Title = "kept"
+			deleted := NewExampleModel()
+			deleted.Title = "deleted"
+			updated := NewExampleModel()
+			updated.Title = "updated"
+
+			_, err := storage.InsertMany(context.TODO(), []interface{}{kept, deleted, updated})
+			Expect(err).To(BeNil())
+
+			_, err = storage.BulkWrite(context.TODO(), NewBulkWriteBuilder().
+				DeleteMany(NewFilterBuilder().EqualTo("title", "deleted")).
+				UpdateMany(NewFilterBuilder().EqualTo("title", "updated"), NewUpdateBuilder().Set("title", "renamed")))
+			Expect(err).To(BeNil())
+
+			count, err := storage.CountByFilter(context.TODO(), bson.M{})
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(int64(2)))
+
+			Expect(storage.GetOneByFilter(context.TODO(), bson.M{"title": "kept"}, &ExampleModel{})).To(BeNil())
+			Expect(storage.GetOneByFilter(context.TODO(), bson.M{"title": "renamed"}, &ExampleModel{})).To(BeNil())
+		})
+
+		It("should run after callbacks of inserted and replaced documents", func() {
+			existing := &CallbackModel{Title: "existing"}
+			_, err := storage.InsertOne(context.TODO(), existing)
+			Expect(err).To(BeNil())
+
+			inserted := &CallbackModel{Title: "inserted"}
+			replaced := &CallbackModel{Title: "replaced"}
+
+			_, err = storage.BulkWrite(context.TODO(), NewBulkWriteBuilder().
+				InsertOne(inserted).
+				ReplaceOne(bson.M{"_id": existing.ID}, replaced))
+			Expect(err).To(BeNil())
+
+			Expect(inserted.Calls).To(Equal([]string{"BeforeInsert", "AfterInsert"}))
+			Expect(replaced.Calls).To(Equal([]string{"BeforeUpdate", "AfterUpdate"}))
+		})
+
+		It("should not run after callbacks of replaces without matches", func() {
+			missing := &CallbackModel{Title: "missing"}
+
+			_, err := storage.BulkWrite(context.TODO(), NewBulkWriteBuilder().
+				ChunkSize(1).
+				InsertOne(&CallbackModel{Title: "inserted"}).
+				ReplaceOne(bson.M{"title": "missing"}, missing))
+			Expect(err).To(BeNil())
+
+			Expect(missing.Calls).To(Equal([]string{"BeforeUpdate"}))
+		})
+
+		It("should stop at the first failure in ordered mode", func() {
+			m := NewExampleModel()
+			_, err := storage.InsertOne(context.TODO(), m)
+			Expect(err).To(BeNil())
+
+			b := NewBulkWriteBuilder().
+				ChunkSize(1).
+				InsertOne(m).
+				InsertOne(NewExampleModel())
+
+			res, err := storage.BulkWrite(context.TODO(), b)
+
+			Expect(err).NotTo(BeNil())
+			Expect(len(res.Errors)).To(Equal(1))
+			Expect(res.InsertedIDs).To(BeEmpty())
+		})
+	})
+})
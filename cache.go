@@ -0,0 +1,125 @@
+package mongol
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	timecop "github.com/bluele/go-timecop"
+)
+
+const (
+	// DefaultCacheSize is a maximum number of entries of LRUCache created with non-positive size
+	DefaultCacheSize = 10000
+)
+
+// Cache keeps encoded documents of CachedCollection
+type Cache interface {
+	// Get() returns the value and true if the key exists and is not expired
+	Get(key string) ([]byte, bool)
+	// Set() stores the value, non-positive ttl means the entry does not expire
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(key string)
+}
+
+var (
+	_ Cache = (*LRUCache)(nil)
+)
+
+// LRUCache is an in-memory Cache which evicts the least recently used entries
+type LRUCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUCache() is a constructor for LRUCache struct
+func NewLRUCache(size int) *LRUCache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+
+	return &LRUCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get() returns the value and true if the key exists and is not expired
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*lruEntry)
+	if !e.expiresAt.IsZero() && !timecop.Now().Before(e.expiresAt) {
+		c.remove(el)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return e.value, true
+}
+
+// Set() stores the value, non-positive ttl means the entry does not expire
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = timecop.Now().Add(ttl)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete() removes the key
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+}
+
+// Len() returns a number of entries including expired ones which were not evicted yet
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key)
+}
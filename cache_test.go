@@ -0,0 +1,66 @@
+package mongol_test
+
+import (
+	"time"
+
+	timecop "github.com/bluele/go-timecop"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("LRUCache", func() {
+	var (
+		cache *LRUCache
+	)
+
+	BeforeEach(func() {
+		cache = NewLRUCache(2)
+	})
+
+	It("should get and delete values", func() {
+		cache.Set("a", []byte("1"), 0)
+
+		v, ok := cache.Get("a")
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal([]byte("1")))
+
+		cache.Delete("a")
+
+		_, ok = cache.Get("a")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should evict the least recently used value", func() {
+		cache.Set("a", []byte("1"), 0)
+		cache.Set("b", []byte("2"), 0)
+		cache.Get("a")
+		cache.Set("c", []byte("3"), 0)
+
+		Expect(cache.Len()).To(Equal(2))
+
+		_, ok := cache.Get("b")
+		Expect(ok).To(BeFalse())
+
+		_, ok = cache.Get("a")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should expire values", func() {
+		now := time.Now()
+		timecop.Freeze(now)
+		defer timecop.Return()
+
+		cache.Set("a", []byte("1"), time.Minute)
+
+		_, ok := cache.Get("a")
+		Expect(ok).To(BeTrue())
+
+		timecop.Freeze(now.Add(time.Minute))
+
+		_, ok = cache.Get("a")
+		Expect(ok).To(BeFalse())
+		Expect(cache.Len()).To(Equal(0))
+	})
+})
@@ -0,0 +1,433 @@
+package mongol
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// DefaultCacheTTL is a lifetime of cached documents
+	DefaultCacheTTL = time.Minute
+
+	// idVersionStripes is a number of versions shared by cached documents
+	idVersionStripes = 256
+)
+
+var (
+	_ Storage = (*CachedCollection)(nil)
+)
+
+// CachedCollection is a read-through cache decorator of Storage
+// GetOneByID() and GetOneByFilter() results are cached, writes made through the decorator
+// invalidate affected entries, writes of other processes are visible after TTL
+// Cache hits do not run hooks of the underlying collection, AfterFind callbacks are run
+type CachedCollection struct {
+	Storage
+	Cache Cache
+	// TTL of cached documents
+	TTL time.Duration
+	// NotFoundTTL enables caching of ErrDocumentNotFound if positive
+	NotFoundTTL time.Duration
+
+	// idGen invalidates all documents, filterGen invalidates GetOneByFilter() results,
+	// idVersions invalidate documents hashed to the stripe, so a read started before a write
+	// stores its result under the previous key
+	idGen      uint64
+	filterGen  uint64
+	idVersions [idVersionStripes]uint64
+}
+
+// NewCachedCollection() is a constructor for CachedCollection struct,
+// NewLRUCache(DefaultCacheSize) is used if cache is nil
+func NewCachedCollection(s Storage, cache Cache, ttl time.Duration) *CachedCollection {
+	if cache == nil {
+		cache = NewLRUCache(DefaultCacheSize)
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &CachedCollection{
+		Storage: s,
+		Cache:   cache,
+		TTL:     ttl,
+	}
+}
+
+// BSONRegistry() returns the codecs registry of the wrapped storage
+func (c *CachedCollection) BSONRegistry() *bsoncodec.Registry {
+	return registryOf(c.Storage)
+}
+
+// DocumentID() returns the string form of the document key formatted by the wrapped storage
+func (c *CachedCollection) DocumentID(m Document) string {
+	return documentIDOf(c.Storage, m)
+}
+
+// GetOneByID() returns the cached document or loads it from the collection,
+// requests with FindOneOptions or WithPopulate() are not cached
+func (c *CachedCollection) GetOneByID(ctx context.Context, recordID string, m Document, opts ...*options.FindOneOptions) error {
+	if len(opts) > 0 || len(PopulateFromContext(ctx)) > 0 {
+		return c.Storage.GetOneByID(ctx, recordID, m, opts...)
+	}
+
+	return c.readThrough(ctx, c.idKey(ctx, recordID), m, func() error {
+		return c.Storage.GetOneByID(ctx, recordID, m)
+	})
+}
+
+// GetOneByFilter() returns the cached document or loads it from the collection,
+// requests with FindOneOptions or WithPopulate() are not cached
+func (c *CachedCollection) GetOneByFilter(ctx context.Context, filter interface{}, m Document, opts ...*options.FindOneOptions) error {
+	if len(opts) > 0 || len(PopulateFromContext(ctx)) > 0 {
+		return c.Storage.GetOneByFilter(ctx, filter, m, opts...)
+	}
+
+	key, err := c.filterKey(ctx, filter)
+	if err != nil {
+		return c.Storage.GetOneByFilter(ctx, filter, m)
+	}
+
+	return c.readThrough(ctx, key, m, func() error {
+		return c.Storage.GetOneByFilter(ctx, filter, m)
+	})
+}
+
+// InsertOne() inserts the document and invalidates cached results
+func (c *CachedCollection) InsertOne(ctx context.Context, m Document, opts ...*options.InsertOneOptions) (string, error) {
+	id, err := c.Storage.InsertOne(ctx, m, opts...)
+	c.invalidate(ctx, id)
+
+	return id, err
+}
+
+// InsertMany() inserts the documents and invalidates cached results
+func (c *CachedCollection) InsertMany(ctx context.Context, docs []interface{}, opts ...*options.InsertManyOptions) ([]string, error) {
+	ids, err := c.Storage.InsertMany(ctx, docs, opts...)
+	c.invalidateAll()
+
+	return ids, err
+}
+
+// BulkWrite() executes the operations and invalidates cached results
+func (c *CachedCollection) BulkWrite(ctx context.Context, b *BulkWriteBuilder) (*BulkWriteResult, error) {
+	res, err := c.Storage.BulkWrite(ctx, b)
+	c.invalidateAll()
+
+	return res, err
+}
+
+// UpdateOne() updates the document and invalidates cached results
+func (c *CachedCollection) UpdateOne(ctx context.Context, m Document, opts ...*options.UpdateOptions) error {
+	err := c.Storage.UpdateOne(ctx, m, opts...)
+	c.invalidate(ctx, documentIDOf(c.Storage, m))
+
+	return err
+}
+
+// UpdateManyByFilter() updates the documents and invalidates cached results
+func (c *CachedCollection) UpdateManyByFilter(ctx context.Context, filter interface{}, m Document, opts ...*options.UpdateOptions) error {
+	err := c.Storage.UpdateManyByFilter(ctx, filter, m, opts...)
+	c.invalidateAll()
+
+	return err
+}
+
+// PatchOneByID() patches the document and invalidates cached results
+func (c *CachedCollection) PatchOneByID(ctx context.Context, recordID string, patch []byte, m Document) error {
+	err := c.Storage.PatchOneByID(ctx, recordID, patch, m)
+	c.invalidate(ctx, recordID)
+
+	return err
+}
+
+// Save() updates changed fields of the document and invalidates cached results
+func (c *CachedCollection) Save(ctx context.Context, m Document, opts ...*options.UpdateOptions) error {
+	err := c.Storage.Save(ctx, m, opts...)
+	c.invalidate(ctx, documentIDOf(c.Storage, m))
+
+	return err
+}
+
+// UpdateOneWithResult() updates the document and invalidates cached results
+func (c *CachedCollection) UpdateOneWithResult(ctx context.Context, m Document, opts ...*options.UpdateOptions) (*WriteResult, error) {
+	res, err := c.Storage.UpdateOneWithResult(ctx, m, opts...)
+	c.invalidate(ctx, documentIDOf(c.Storage, m))
+
+	return res, err
+}
+
+// UpdateManyByFilterWithResult() updates the documents and invalidates cached results
+func (c *CachedCollection) UpdateManyByFilterWithResult(
+	ctx context.Context,
+	filter interface{},
+	m Document,
+	opts ...*options.UpdateOptions,
+) (*WriteResult, error) {
+	res, err := c.Storage.UpdateManyByFilterWithResult(ctx, filter, m, opts...)
+	c.invalidateAll()
+
+	return res, err
+}
+
+// UpdateMany() updates the documents and invalidates cached results
+func (c *CachedCollection) UpdateMany(
+	ctx context.Context,
+	filter, update interface{},
+	opts ...*options.UpdateOptions,
+) (*mongo.UpdateResult, error) {
+	res, err := c.Storage.UpdateMany(ctx, filter, update, opts...)
+	c.invalidateAll()
+
+	return res, err
+}
+
+// UpsertOne() upserts the document and invalidates cached results
+func (c *CachedCollection) UpsertOne(ctx context.Context, filter interface{}, update bson.M, m Document) (Document, error) {
+	doc, err := c.Storage.UpsertOne(ctx, filter, update, m)
+	c.invalidate(ctx, documentIDOf(c.Storage, m))
+
+	return doc, err
+}
+
+// FindAndUpdateOne() updates the document and invalidates cached results
+func (c *CachedCollection) FindAndUpdateOne(ctx context.Context, filter interface{}, update bson.M, m Document) (Document, error) {
+	doc, err := c.Storage.FindAndUpdateOne(ctx, filter, update, m)
+	c.invalidate(ctx, documentIDOf(c.Storage, m))
+
+	return doc, err
+}
+
+// ReplaceOne() replaces the document and invalidates cached results
+func (c *CachedCollection) ReplaceOne(
+	ctx context.Context,
+	filter interface{},
+	m Document,
+	opts ...*options.ReplaceOptions,
+) (*mongo.UpdateResult, error) {
+	res, err := c.Storage.ReplaceOne(ctx, filter, m, opts...)
+	c.invalidateAll()
+
+	return res, err
+}
+
+// ReplaceOneByID() replaces the document and invalidates cached results
+func (c *CachedCollection) ReplaceOneByID(
+	ctx context.Context,
+	recordID string,
+	m Document,
+	opts ...*options.ReplaceOptions,
+) (*mongo.UpdateResult, error) {
+	res, err := c.Storage.ReplaceOneByID(ctx, recordID, m, opts...)
+	c.invalidate(ctx, recordID)
+
+	return res, err
+}
+
+// DeleteManyByFilter() deletes the documents and invalidates cached results
+func (c *CachedCollection) DeleteManyByFilter(
+	ctx context.Context,
+	filter interface{},
+	opts ...*options.DeleteOptions,
+) (*mongo.DeleteResult, error) {
+	res, err := c.Storage.DeleteManyByFilter(ctx, filter, opts...)
+	c.invalidateAll()
+
+	return res, err
+}
+
+// DeleteOneByID() deletes the document and invalidates cached results
+func (c *CachedCollection) DeleteOneByID(ctx context.Context, docID string) error {
+	err := c.Storage.DeleteOneByID(ctx, docID)
+	c.invalidate(ctx, docID)
+
+	return err
+}
+
+// DeleteOne() deletes the document and invalidates cached results
+func (c *CachedCollection) DeleteOne(ctx context.Context, m Document) error {
+	err := c.Storage.DeleteOne(ctx, m)
+	c.invalidate(ctx, documentIDOf(c.Storage, m))
+
+	return err
+}
+
+// DeleteAll() deletes the documents and invalidates cached results
+func (c *CachedCollection) DeleteAll(ctx context.Context) error {
+	err := c.Storage.DeleteAll(ctx)
+	c.invalidateAll()
+
+	return err
+}
+
+// readThrough() decodes the cached document into m or calls load() and caches its result
+func (c *CachedCollection) readThrough(ctx context.Context, key string, m Document, load func() error) error {
+	if b, ok := c.Cache.Get(key); ok {
+		// empty value is a cached ErrDocumentNotFound
+		if len(b) == 0 {
+			return ErrDocumentNotFound
+		}
+
+		if err := bson.UnmarshalWithRegistry(registryOf(c.Storage), b, m); err != nil {
+			return err
+		}
+
+		if err := setDecodedID(m, b); err != nil {
+			return err
+		}
+
+		if err := runAfterFind(ctx, m); err != nil {
+			return err
+		}
+
+		return trackChanges(registryOf(c.Storage), m)
+	}
+
+	err := load()
+
+	switch {
+	case errors.Is(err, ErrDocumentNotFound) && c.NotFoundTTL > 0:
+		c.Cache.Set(key, []byte{}, c.NotFoundTTL)
+	case err == nil:
+		if b, marshalErr := bson.MarshalWithRegistry(registryOf(c.Storage), m); marshalErr == nil {
+			c.Cache.Set(key, b, c.TTL)
+		}
+	}
+
+	return err
+}
+
+// invalidate() removes the cached document and all cached GetOneByFilter() results,
+// the document version is changed before, so reads in progress do not restore the entry
+func (c *CachedCollection) invalidate(ctx context.Context, recordID string) {
+	atomic.AddUint64(&c.filterGen, 1)
+
+	if recordID != "" {
+		key := c.idKey(ctx, recordID)
+		atomic.AddUint64(c.idVersion(ctx, recordID), 1)
+		c.Cache.Delete(key)
+	}
+}
+
+// invalidateAll() removes all cached results
+func (c *CachedCollection) invalidateAll() {
+	atomic.AddUint64(&c.idGen, 1)
+	atomic.AddUint64(&c.filterGen, 1)
+}
+
+func (c *CachedCollection) idKey(ctx context.Context, recordID string) string {
+	return c.keyPrefix(ctx) +
+		"id:" + strconv.FormatUint(atomic.LoadUint64(&c.idGen), 10) +
+		":" + strconv.FormatUint(atomic.LoadUint64(c.idVersion(ctx, recordID)), 10) +
+		":" + recordID
+}
+
+// idVersion() returns the version of the stripe the document belongs to
+func (c *CachedCollection) idVersion(ctx context.Context, recordID string) *uint64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(c.keyPrefix(ctx) + recordID))
+
+	return &c.idVersions[h.Sum32()%idVersionStripes]
+}
+
+// filterKey() encodes the filter to canonical extended JSON, keys of maps are sorted
+func (c *CachedCollection) filterKey(ctx context.Context, filter interface{}) (string, error) {
+	filter, err := buildFilter(filter)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := bson.MarshalExtJSONWithRegistry(registryOf(c.Storage), canonicalFilter(filter), true, false)
+	if err != nil {
+		return "", err
+	}
+
+	return c.keyPrefix(ctx) +
+		"filter:" + strconv.FormatUint(atomic.LoadUint64(&c.idGen), 10) +
+		":" + strconv.FormatUint(atomic.LoadUint64(&c.filterGen), 10) +
+		":" + string(b), nil
+}
+
+// canonicalFilter() replaces maps of the filter with documents sorted by keys,
+// order of bson.D is kept as it is significant for MongoDB
+func canonicalFilter(v interface{}) interface{} {
+	switch f := v.(type) {
+	case bson.M:
+		return canonicalFilter(map[string]interface{}(f))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(f))
+		for k := range f {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		d := make(bson.D, 0, len(f))
+		for _, k := range keys {
+			d = append(d, primitive.E{Key: k, Value: canonicalFilter(f[k])})
+		}
+
+		return d
+	case bson.D:
+		d := make(bson.D, 0, len(f))
+		for _, e := range f {
+			d = append(d, primitive.E{Key: e.Key, Value: canonicalFilter(e.Value)})
+		}
+
+		return d
+	case bson.A:
+		return canonicalFilter([]interface{}(f))
+	case []interface{}:
+		a := make(bson.A, 0, len(f))
+		for _, e := range f {
+			a = append(a, canonicalFilter(e))
+		}
+
+		return a
+	default:
+		return v
+	}
+}
+
+// keyPrefix() separates collections and tenants sharing one Cache
+func (c *CachedCollection) keyPrefix(ctx context.Context) string {
+	coll := c.Collection()
+	prefix := coll.Database().Name() + "." + coll.Name() + ":"
+
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		prefix += "tenant:" + tenantID + ":"
+	}
+
+	return prefix
+}
+
+// registryOf() returns the codecs registry of storages exposing it like BaseCollection
+func registryOf(s Storage) *bsoncodec.Registry {
+	if r, ok := s.(interface{ BSONRegistry() *bsoncodec.Registry }); ok {
+		return r.BSONRegistry()
+	}
+
+	return bson.DefaultRegistry
+}
+
+// documentIDOf() returns the string form of the document key formatted by storages exposing it
+// like BaseCollection, GetHexID() is used otherwise
+func documentIDOf(s Storage, m Document) string {
+	if f, ok := s.(interface{ DocumentID(Document) string }); ok {
+		return f.DocumentID(m)
+	}
+
+	return m.GetHexID()
+}
@@ -0,0 +1,171 @@
+package mongol_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/wajox/mongol"
+)
+
+// memoryStorage serves documents from memory and counts reads
+type memoryStorage struct {
+	*BaseCollection
+	docs  map[string]*ExampleModel
+	reads int
+	// onRead is called after the document is read
+	onRead func()
+}
+
+func (s *memoryStorage) GetOneByID(ctx context.Context, recordID string, m Document, opts ...*options.FindOneOptions) error {
+	s.reads++
+
+	doc, ok := s.docs[recordID]
+	if !ok {
+		return ErrDocumentNotFound
+	}
+
+	*(m.(*ExampleModel)) = *doc
+
+	if s.onRead != nil {
+		s.onRead()
+	}
+
+	return nil
+}
+
+func (s *memoryStorage) GetOneByFilter(ctx context.Context, filter interface{}, m Document, opts ...*options.FindOneOptions) error {
+	return s.GetOneByID(ctx, filter.(bson.M)["title"].(string), m, opts...)
+}
+
+func (s *memoryStorage) DeleteOneByID(ctx context.Context, docID string) error {
+	delete(s.docs, docID)
+
+	return nil
+}
+
+func (s *memoryStorage) UpdateMany(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return &mongo.UpdateResult{}, nil
+}
+
+var _ = Describe("CachedCollection", func() {
+	var (
+		storage *memoryStorage
+		cached  *CachedCollection
+		doc     *ExampleModel
+	)
+
+	BeforeEach(func() {
+		doc = NewExampleModel()
+		doc.ID = primitive.NewObjectID()
+
+		base := newTestCollection("cached_test")
+
+		storage = &memoryStorage{
+			BaseCollection: base,
+			docs:           map[string]*ExampleModel{doc.GetHexID(): doc},
+		}
+
+		cached = NewCachedCollection(storage, nil, time.Minute)
+	})
+
+	Describe("GetOneByID()", func() {
+		It("should read through the cache", func() {
+			for i := 0; i < 3; i++ {
+				m := &ExampleModel{}
+				Expect(cached.GetOneByID(context.TODO(), doc.GetHexID(), m)).To(BeNil())
+				Expect(m.ID).To(Equal(doc.ID))
+				Expect(m.Title).To(Equal(doc.Title))
+			}
+
+			Expect(storage.reads).To(Equal(1))
+		})
+
+		It("should not cache requests with options", func() {
+			Expect(cached.GetOneByID(context.TODO(), doc.GetHexID(), &ExampleModel{}, options.FindOne())).To(BeNil())
+			Expect(cached.GetOneByID(context.TODO(), doc.GetHexID(), &ExampleModel{}, options.FindOne())).To(BeNil())
+
+			Expect(storage.reads).To(Equal(2))
+		})
+
+		It("should separate tenants", func() {
+			Expect(cached.GetOneByID(WithTenant(context.TODO(), "acme"), doc.GetHexID(), &ExampleModel{})).To(BeNil())
+			Expect(cached.GetOneByID(WithTenant(context.TODO(), "globex"), doc.GetHexID(), &ExampleModel{})).To(BeNil())
+
+			Expect(storage.reads).To(Equal(2))
+		})
+
+		It("should invalidate the document on delete", func() {
+			Expect(cached.GetOneByID(context.TODO(), doc.GetHexID(), &ExampleModel{})).To(BeNil())
+			Expect(cached.DeleteOneByID(context.TODO(), doc.GetHexID())).To(BeNil())
+
+			Expect(cached.GetOneByID(context.TODO(), doc.GetHexID(), &ExampleModel{})).To(Equal(ErrDocumentNotFound))
+			Expect(storage.reads).To(Equal(2))
+		})
+
+		It("should not cache documents read before a concurrent write", func() {
+			storage.onRead = func() {
+				storage.onRead = nil
+				Expect(cached.DeleteOneByID(context.TODO(), doc.GetHexID())).To(BeNil())
+			}
+
+			Expect(cached.GetOneByID(context.TODO(), doc.GetHexID(), &ExampleModel{})).To(BeNil())
+
+			Expect(cached.GetOneByID(context.TODO(), doc.GetHexID(), &ExampleModel{})).To(Equal(ErrDocumentNotFound))
+			Expect(storage.reads).To(Equal(2))
+		})
+
+		It("should cache ErrDocumentNotFound", func() {
+			missingID := primitive.NewObjectID().Hex()
+
+			Expect(cached.GetOneByID(context.TODO(), missingID, &ExampleModel{})).To(Equal(ErrDocumentNotFound))
+			Expect(cached.GetOneByID(context.TODO(), missingID, &ExampleModel{})).To(Equal(ErrDocumentNotFound))
+			Expect(storage.reads).To(Equal(2))
+
+			cached.NotFoundTTL = time.Minute
+
+			Expect(cached.GetOneByID(context.TODO(), missingID, &ExampleModel{})).To(Equal(ErrDocumentNotFound))
+			Expect(cached.GetOneByID(context.TODO(), missingID, &ExampleModel{})).To(Equal(ErrDocumentNotFound))
+			Expect(storage.reads).To(Equal(3))
+		})
+	})
+
+	Describe("GetOneByFilter()", func() {
+		It("should share results of equal filters", func() {
+			first, second := "john", "john"
+
+			Expect(cached.GetOneByFilter(
+				context.TODO(),
+				bson.M{"title": doc.GetHexID(), "author": &first, "tags": bson.A{bson.M{"a": 1, "b": 2}}},
+				&ExampleModel{},
+			)).To(BeNil())
+			Expect(cached.GetOneByFilter(
+				context.TODO(),
+				bson.M{"tags": bson.A{bson.M{"b": 2, "a": 1}}, "author": &second, "title": doc.GetHexID()},
+				&ExampleModel{},
+			)).To(BeNil())
+
+			Expect(storage.reads).To(Equal(1))
+		})
+
+		It("should invalidate results on writes of many documents", func() {
+			filter := bson.M{"title": doc.GetHexID()}
+
+			Expect(cached.GetOneByFilter(context.TODO(), filter, &ExampleModel{})).To(BeNil())
+			Expect(cached.GetOneByFilter(context.TODO(), bson.M{"title": doc.GetHexID()}, &ExampleModel{})).To(BeNil())
+			Expect(storage.reads).To(Equal(1))
+
+			_, err := cached.UpdateMany(context.TODO(), bson.M{}, bson.M{"$set": bson.M{"title": "updated"}})
+			Expect(err).To(BeNil())
+
+			Expect(cached.GetOneByFilter(context.TODO(), filter, &ExampleModel{})).To(BeNil())
+			Expect(storage.reads).To(Equal(2))
+		})
+	})
+})
@@ -0,0 +1,121 @@
+package mongol
+
+import (
+	"context"
+	"reflect"
+)
+
+// BeforeInsertCallback is implemented by documents which have to be prepared before insertion
+type BeforeInsertCallback interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// AfterInsertCallback is implemented by documents which have to be notified after insertion
+type AfterInsertCallback interface {
+	AfterInsert(ctx context.Context) error
+}
+
+// BeforeUpdateCallback is implemented by documents which have to be prepared before update or replacement
+type BeforeUpdateCallback interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterUpdateCallback is implemented by documents which have to be notified after update or replacement
+type AfterUpdateCallback interface {
+	AfterUpdate(ctx context.Context) error
+}
+
+// AfterFindCallback is implemented by documents which have to be processed after decoding
+type AfterFindCallback interface {
+	AfterFind(ctx context.Context) error
+}
+
+// BeforeDeleteCallback is implemented by documents which have to be checked before deletion
+type BeforeDeleteCallback interface {
+	BeforeDelete(ctx context.Context) error
+}
+
+// AfterDeleteCallback is implemented by documents which have to be notified after deletion
+type AfterDeleteCallback interface {
+	AfterDelete(ctx context.Context) error
+}
+
+func runBeforeInsert(ctx context.Context, doc interface{}) error {
+	if cb, ok := doc.(BeforeInsertCallback); ok {
+		return cb.BeforeInsert(ctx)
+	}
+
+	return nil
+}
+
+func runAfterInsert(ctx context.Context, doc interface{}) error {
+	if cb, ok := doc.(AfterInsertCallback); ok {
+		return cb.AfterInsert(ctx)
+	}
+
+	return nil
+}
+
+func runBeforeUpdate(ctx context.Context, doc interface{}) error {
+	if cb, ok := doc.(BeforeUpdateCallback); ok {
+		return cb.BeforeUpdate(ctx)
+	}
+
+	return nil
+}
+
+func runAfterUpdate(ctx context.Context, doc interface{}) error {
+	if cb, ok := doc.(AfterUpdateCallback); ok {
+		return cb.AfterUpdate(ctx)
+	}
+
+	return nil
+}
+
+func runAfterFind(ctx context.Context, doc interface{}) error {
+	if cb, ok := doc.(AfterFindCallback); ok {
+		return cb.AfterFind(ctx)
+	}
+
+	return nil
+}
+
+func runBeforeDelete(ctx context.Context, doc interface{}) error {
+	if cb, ok := doc.(BeforeDeleteCallback); ok {
+		return cb.BeforeDelete(ctx)
+	}
+
+	return nil
+}
+
+func runAfterDelete(ctx context.Context, doc interface{}) error {
+	if cb, ok := doc.(AfterDeleteCallback); ok {
+		return cb.AfterDelete(ctx)
+	}
+
+	return nil
+}
+
+// runAfterFindAll() runs AfterFind callbacks for every element of the decoded slice
+// docs should be a pointer to a slice as it is passed to FindAllByFilter()
+func runAfterFindAll(ctx context.Context, docs interface{}) error {
+	v := reflect.ValueOf(docs)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return nil
+	}
+
+	l := v.Elem()
+
+	for i := 0; i < l.Len(); i++ {
+		el := l.Index(i)
+		if el.Kind() != reflect.Ptr && el.CanAddr() {
+			el = el.Addr()
+		}
+
+		if err := runAfterFind(ctx, el.Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/wajox/mongol"
+)
+
+var errCallback = errors.New("callback error")
+
+type CallbackModel struct {
+	BaseDocument `bson:",inline"`
+
+	Title  string   `json:"title,omitempty" bson:"title,omitempty"`
+	Slug   string   `json:"slug,omitempty" bson:"slug,omitempty"`
+	FailOn string   `json:"-" bson:"-"`
+	Calls  []string `json:"-" bson:"-"`
+}
+
+func (m *CallbackModel) call(name string) error {
+	m.Calls = append(m.Calls, name)
+
+	if m.FailOn == name {
+		return errCallback
+	}
+
+	return nil
+}
+
+func (m *CallbackModel) BeforeInsert(_ context.Context) error {
+	m.Slug = strings.ToLower(m.Title)
+
+	return m.call("BeforeInsert")
+}
+
+func (m *CallbackModel) AfterInsert(_ context.Context) error {
+	return m.call("AfterInsert")
+}
+
+func (m *CallbackModel) BeforeUpdate(_ context.Context) error {
+	m.Slug = strings.ToLower(m.Title)
+
+	return m.call("BeforeUpdate")
+}
+
+func (m *CallbackModel) AfterUpdate(_ context.Context) error {
+	return m.call("AfterUpdate")
+}
+
+func (m *CallbackModel) AfterFind(_ context.Context) error {
+	return m.call("AfterFind")
+}
+
+func (m *CallbackModel) BeforeDelete(_ context.Context) error {
+	return m.call("BeforeDelete")
+}
+
+func (m *CallbackModel) AfterDelete(_ context.Context) error {
+	return m.call("AfterDelete")
+}
+
+// nolint
+var _ = Describe("Document callbacks", func() {
+	var (
+		storage *BaseCollection
+	)
+
+	BeforeEach(func() {
+		storage = newTestCollection("callbacks_test")
+	})
+
+	Context("with failing before callbacks", func() {
+		It("should not insert the document", func() {
+			m := &CallbackModel{Title: "Title", FailOn: "BeforeInsert"}
+
+			_, err := storage.InsertOne(context.TODO(), m)
+
+			Expect(err).To(Equal(errCallback))
+			Expect(m.Calls).To(Equal([]string{"BeforeInsert"}))
+		})
+
+		It("should not insert many documents", func() {
+			m1 := &CallbackModel{Title: "First"}
+			m2 := &CallbackModel{Title: "Second", FailOn: "BeforeInsert"}
+
+			_, err := storage.InsertMany(context.TODO(), []interface{}{m1, m2})
+
+			Expect(err).To(Equal(errCallback))
+			Expect(m1.Calls).To(Equal([]string{"BeforeInsert"}))
+			Expect(m2.Calls).To(Equal([]string{"BeforeInsert"}))
+		})
+
+		It("should not update the document", func() {
+			m := &CallbackModel{Title: "Title", FailOn: "BeforeUpdate"}
+
+			Expect(storage.UpdateOne(context.TODO(), m)).To(Equal(errCallback))
+			Expect(m.Calls).To(Equal([]string{"BeforeUpdate"}))
+		})
+
+		It("should not replace the document", func() {
+			m := &CallbackModel{Title: "Title", FailOn: "BeforeUpdate"}
+
+			_, err := storage.ReplaceOne(context.TODO(), bson.M{}, m)
+
+			Expect(err).To(Equal(errCallback))
+		})
+
+		It("should not delete the document", func() {
+			m := &CallbackModel{Title: "Title", FailOn: "BeforeDelete"}
+
+			Expect(storage.DeleteOne(context.TODO(), m)).To(Equal(errCallback))
+			Expect(m.Calls).To(Equal([]string{"BeforeDelete"}))
+		})
+	})
+
+	Describe("methods", func() {
+		AfterEach(func() {
+			storage.DeleteAll(context.TODO())
+		})
+
+		It("should run insert, find and delete callbacks", func() {
+			m := &CallbackModel{Title: "Some Title"}
+
+			id, err := storage.InsertOne(context.TODO(), m)
+			Expect(err).To(BeNil())
+			Expect(m.Calls).To(Equal([]string{"BeforeInsert", "AfterInsert"}))
+
+			found := &CallbackModel{}
+			Expect(storage.GetOneByID(context.TODO(), id, found)).To(BeNil())
+			Expect(found.Slug).To(Equal("some title"))
+			Expect(found.Calls).To(Equal([]string{"AfterFind"}))
+
+			Expect(storage.DeleteOne(context.TODO(), found)).To(BeNil())
+			Expect(found.Calls).To(Equal([]string{"AfterFind", "BeforeDelete", "AfterDelete"}))
+			Expect(errors.Is(storage.DeleteOne(context.TODO(), found), ErrDocumentNotFound)).To(BeTrue())
+		})
+
+		It("should run callbacks for every document", func() {
+			m1 := &CallbackModel{Title: "First"}
+			m2 := &CallbackModel{Title: "Second"}
+
+			_, err := storage.InsertMany(context.TODO(), []interface{}{m1, m2})
+			Expect(err).To(BeNil())
+			Expect(m1.Calls).To(Equal([]string{"BeforeInsert", "AfterInsert"}))
+			Expect(m2.Calls).To(Equal([]string{"BeforeInsert", "AfterInsert"}))
+
+			l, err := storage.GetManyByFilter(context.TODO(), bson.M{}, func() Document {
+				return &CallbackModel{}
+			})
+			Expect(err).To(BeNil())
+			Expect(len(l)).To(Equal(2))
+
+			for _, m := range l {
+				Expect(m.(*CallbackModel).Calls).To(Equal([]string{"AfterFind"}))
+			}
+
+			all := []CallbackModel{}
+			Expect(storage.FindAllByFilter(context.TODO(), bson.M{}, &all)).To(BeNil())
+			Expect(len(all)).To(Equal(2))
+
+			for i := range all {
+				Expect(all[i].Calls).To(Equal([]string{"AfterFind"}))
+			}
+		})
+
+		It("should run update callbacks", func() {
+			m := &CallbackModel{Title: "Title"}
+
+			_, err := storage.InsertOne(context.TODO(), m)
+			Expect(err).To(BeNil())
+
+			m.Title = "New Title"
+			Expect(storage.UpdateOne(context.TODO(), m)).To(BeNil())
+			Expect(m.Slug).To(Equal("new title"))
+			Expect(m.Calls).To(Equal([]string{"BeforeInsert", "AfterInsert", "BeforeUpdate", "AfterUpdate"}))
+		})
+	})
+})
@@ -0,0 +1,581 @@
+package mongol
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	timecop "github.com/bluele/go-timecop"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	DefaultCircuitConsecutiveFailures = 5
+	DefaultCircuitFailureRate         = 0.5
+	DefaultCircuitMinRequests         = 20
+	DefaultCircuitWindow              = time.Second * 10
+	DefaultCircuitOpenTimeout         = time.Second * 5
+)
+
+// CircuitState
+type CircuitState int
+
+const (
+	// CircuitClosed passes operations to the storage
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects operations with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen probes the storage with Ping()
+	CircuitHalfOpen
+)
+
+var (
+	_ Storage = (*CircuitBreaker)(nil)
+)
+
+// String() returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker is a decorator of Storage which fails fast with ErrCircuitOpen
+// after consecutive failures or a high failure rate, once OpenTimeout passes
+// the next operation probes the storage with Ping() and closes the circuit if it succeeds
+type CircuitBreaker struct {
+	Storage
+	// ConsecutiveFailures trips the circuit, 0 disables the check
+	ConsecutiveFailures int
+	// FailureRate of operations in Window trips the circuit, 0 disables the check
+	FailureRate float64
+	// MinRequests in Window required to check FailureRate
+	MinRequests int
+	Window      time.Duration
+	// OpenTimeout is a time before the half-open probe
+	OpenTimeout time.Duration
+	// IsFailure classifies errors, IsCircuitFailure() is used if nil
+	IsFailure func(err error) bool
+	// OnStateChange is called after every change of the state
+	OnStateChange func(from, to CircuitState)
+
+	mu          sync.Mutex
+	state       CircuitState
+	consecutive int
+	requests    int
+	failures    int
+	windowStart time.Time
+	openedAt    time.Time
+}
+
+// NewCircuitBreaker() is a constructor for CircuitBreaker struct
+func NewCircuitBreaker(s Storage) *CircuitBreaker {
+	return &CircuitBreaker{
+		Storage:             s,
+		ConsecutiveFailures: DefaultCircuitConsecutiveFailures,
+		FailureRate:         DefaultCircuitFailureRate,
+		MinRequests:         DefaultCircuitMinRequests,
+		Window:              DefaultCircuitWindow,
+		OpenTimeout:         DefaultCircuitOpenTimeout,
+	}
+}
+
+// BSONRegistry() returns the codecs registry of the wrapped storage
+func (c *CircuitBreaker) BSONRegistry() *bsoncodec.Registry {
+	return registryOf(c.Storage)
+}
+
+// DocumentID() returns the string form of the document key formatted by the wrapped storage
+func (c *CircuitBreaker) DocumentID(m Document) string {
+	return documentIDOf(c.Storage, m)
+}
+
+// IsCircuitFailure() returns true for errors of the storage itself,
+// not found documents, validation errors and canceled contexts do not trip the circuit
+func IsCircuitFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, target := range []error{
+		ErrDocumentNotFound,
+		ErrDocumentDuplication,
+		ErrDocumentNotModified,
+		ErrDocumentValidation,
+		ErrWriteConflict,
+		ErrInvalidObjectID,
+		ErrInvalidID,
+		ErrInvalidPatch,
+		ErrPatchTestFailed,
+		ErrTenantMissing,
+		ErrScopeNotRegistered,
+		ErrRelationNotRegistered,
+		ErrCircuitOpen,
+		context.Canceled,
+	} {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+
+	var bwErr *BulkWriteError
+	var biErr *BulkInsertError
+
+	return !errors.As(err, &bwErr) && !errors.As(err, &biErr)
+}
+
+// State() returns the current state of the circuit
+func (c *CircuitBreaker) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state
+}
+
+// allow() returns ErrCircuitOpen if the operation has to be rejected,
+// the open circuit is probed with Ping() once OpenTimeout passes
+func (c *CircuitBreaker) allow(ctx context.Context) error {
+	c.mu.Lock()
+
+	switch {
+	case c.state == CircuitClosed:
+		c.mu.Unlock()
+		return nil
+	case c.state == CircuitHalfOpen || timecop.Now().Sub(c.openedAt) < c.OpenTimeout:
+		c.mu.Unlock()
+		return ErrCircuitOpen
+	}
+
+	notify := c.setState(CircuitHalfOpen)
+	c.mu.Unlock()
+	notify()
+
+	err := c.Storage.Ping(ctx)
+
+	c.mu.Lock()
+	if err != nil {
+		notify = c.open()
+	} else {
+		c.reset()
+		notify = c.setState(CircuitClosed)
+	}
+	c.mu.Unlock()
+	notify()
+
+	if err != nil {
+		return ErrCircuitOpen
+	}
+
+	return nil
+}
+
+// record() counts the result of the operation and trips the circuit if needed
+func (c *CircuitBreaker) record(err error) {
+	isFailure := c.IsFailure
+	if isFailure == nil {
+		isFailure = IsCircuitFailure
+	}
+
+	failed := isFailure(err)
+
+	c.mu.Lock()
+
+	if c.state != CircuitClosed {
+		c.mu.Unlock()
+		return
+	}
+
+	now := timecop.Now()
+	if c.Window > 0 && now.Sub(c.windowStart) >= c.Window {
+		c.requests = 0
+		c.failures = 0
+		c.windowStart = now
+	}
+
+	c.requests++
+
+	if !failed {
+		c.consecutive = 0
+		c.mu.Unlock()
+
+		return
+	}
+
+	c.consecutive++
+	c.failures++
+
+	tripped := c.ConsecutiveFailures > 0 && c.consecutive >= c.ConsecutiveFailures
+	tripped = tripped || (c.FailureRate > 0 &&
+		c.requests >= c.MinRequests &&
+		float64(c.failures)/float64(c.requests) >= c.FailureRate)
+
+	notify := func() {}
+	if tripped {
+		notify = c.open()
+	}
+
+	c.mu.Unlock()
+	notify()
+}
+
+func (c *CircuitBreaker) open() func() {
+	c.openedAt = timecop.Now()
+
+	return c.setState(CircuitOpen)
+}
+
+func (c *CircuitBreaker) reset() {
+	c.consecutive = 0
+	c.requests = 0
+	c.failures = 0
+	c.windowStart = timecop.Now()
+}
+
+// setState() changes the state and returns a function which calls OnStateChange,
+// it has to be called without the lock, so callbacks can use the breaker
+func (c *CircuitBreaker) setState(state CircuitState) func() {
+	from := c.state
+	c.state = state
+
+	if from == state || c.OnStateChange == nil {
+		return func() {}
+	}
+
+	onStateChange := c.OnStateChange
+
+	return func() {
+		onStateChange(from, state)
+	}
+}
+
+// call() runs the operation if the circuit allows it and records its result
+func (c *CircuitBreaker) call(ctx context.Context, fn func() error) error {
+	if err := c.allow(ctx); err != nil {
+		return err
+	}
+
+	err := fn()
+	c.record(err)
+
+	return err
+}
+
+// CreateIndex()
+func (c *CircuitBreaker) CreateIndex(ctx context.Context, k interface{}, o *options.IndexOptions) (name string, err error) {
+	err = c.call(ctx, func() error {
+		name, err = c.Storage.CreateIndex(ctx, k, o)
+		return err
+	})
+
+	return name, err
+}
+
+// InsertOne()
+func (c *CircuitBreaker) InsertOne(ctx context.Context, m Document, opts ...*options.InsertOneOptions) (id string, err error) {
+	err = c.call(ctx, func() error {
+		id, err = c.Storage.InsertOne(ctx, m, opts...)
+		return err
+	})
+
+	return id, err
+}
+
+// InsertMany()
+func (c *CircuitBreaker) InsertMany(ctx context.Context, docs []interface{}, opts ...*options.InsertManyOptions) (ids []string, err error) {
+	err = c.call(ctx, func() error {
+		ids, err = c.Storage.InsertMany(ctx, docs, opts...)
+		return err
+	})
+
+	return ids, err
+}
+
+// BulkWrite()
+func (c *CircuitBreaker) BulkWrite(ctx context.Context, b *BulkWriteBuilder) (res *BulkWriteResult, err error) {
+	err = c.call(ctx, func() error {
+		res, err = c.Storage.BulkWrite(ctx, b)
+		return err
+	})
+
+	return res, err
+}
+
+// UpdateOne()
+func (c *CircuitBreaker) UpdateOne(ctx context.Context, m Document, opts ...*options.UpdateOptions) error {
+	return c.call(ctx, func() error {
+		return c.Storage.UpdateOne(ctx, m, opts...)
+	})
+}
+
+// UpdateManyByFilter()
+func (c *CircuitBreaker) UpdateManyByFilter(ctx context.Context, filter interface{}, m Document, opts ...*options.UpdateOptions) error {
+	return c.call(ctx, func() error {
+		return c.Storage.UpdateManyByFilter(ctx, filter, m, opts...)
+	})
+}
+
+// PatchOneByID()
+func (c *CircuitBreaker) PatchOneByID(ctx context.Context, recordID string, patch []byte, m Document) error {
+	return c.call(ctx, func() error {
+		return c.Storage.PatchOneByID(ctx, recordID, patch, m)
+	})
+}
+
+// Save()
+func (c *CircuitBreaker) Save(ctx context.Context, m Document, opts ...*options.UpdateOptions) error {
+	return c.call(ctx, func() error {
+		return c.Storage.Save(ctx, m, opts...)
+	})
+}
+
+// UpdateOneWithResult()
+func (c *CircuitBreaker) UpdateOneWithResult(
+	ctx context.Context,
+	m Document,
+	opts ...*options.UpdateOptions,
+) (res *WriteResult, err error) {
+	err = c.call(ctx, func() error {
+		res, err = c.Storage.UpdateOneWithResult(ctx, m, opts...)
+		return err
+	})
+
+	return res, err
+}
+
+// UpdateManyByFilterWithResult()
+func (c *CircuitBreaker) UpdateManyByFilterWithResult(
+	ctx context.Context,
+	filter interface{},
+	m Document,
+	opts ...*options.UpdateOptions,
+) (res *WriteResult, err error) {
+	err = c.call(ctx, func() error {
+		res, err = c.Storage.UpdateManyByFilterWithResult(ctx, filter, m, opts...)
+		return err
+	})
+
+	return res, err
+}
+
+// UpdateMany()
+func (c *CircuitBreaker) UpdateMany(
+	ctx context.Context,
+	filter, update interface{},
+	opts ...*options.UpdateOptions,
+) (res *mongo.UpdateResult, err error) {
+	err = c.call(ctx, func() error {
+		res, err = c.Storage.UpdateMany(ctx, filter, update, opts...)
+		return err
+	})
+
+	return res, err
+}
+
+// UpsertOne()
+func (c *CircuitBreaker) UpsertOne(ctx context.Context, filter interface{}, update bson.M, m Document) (doc Document, err error) {
+	err = c.call(ctx, func() error {
+		doc, err = c.Storage.UpsertOne(ctx, filter, update, m)
+		return err
+	})
+
+	return doc, err
+}
+
+// FindAndUpdateOne()
+func (c *CircuitBreaker) FindAndUpdateOne(ctx context.Context, filter interface{}, update bson.M, m Document) (doc Document, err error) {
+	err = c.call(ctx, func() error {
+		doc, err = c.Storage.FindAndUpdateOne(ctx, filter, update, m)
+		return err
+	})
+
+	return doc, err
+}
+
+// ReplaceOne()
+func (c *CircuitBreaker) ReplaceOne(
+	ctx context.Context,
+	filter interface{},
+	m Document,
+	opts ...*options.ReplaceOptions,
+) (res *mongo.UpdateResult, err error) {
+	err = c.call(ctx, func() error {
+		res, err = c.Storage.ReplaceOne(ctx, filter, m, opts...)
+		return err
+	})
+
+	return res, err
+}
+
+// ReplaceOneByID()
+func (c *CircuitBreaker) ReplaceOneByID(
+	ctx context.Context,
+	recordID string,
+	m Document,
+	opts ...*options.ReplaceOptions,
+) (res *mongo.UpdateResult, err error) {
+	err = c.call(ctx, func() error {
+		res, err = c.Storage.ReplaceOneByID(ctx, recordID, m, opts...)
+		return err
+	})
+
+	return res, err
+}
+
+// GetOneByID()
+func (c *CircuitBreaker) GetOneByID(ctx context.Context, recordID string, m Document, opts ...*options.FindOneOptions) error {
+	return c.call(ctx, func() error {
+		return c.Storage.GetOneByID(ctx, recordID, m, opts...)
+	})
+}
+
+// GetOneByFilter()
+func (c *CircuitBreaker) GetOneByFilter(ctx context.Context, filter interface{}, m Document, opts ...*options.FindOneOptions) error {
+	return c.call(ctx, func() error {
+		return c.Storage.GetOneByFilter(ctx, filter, m, opts...)
+	})
+}
+
+// GetManyByFilter()
+func (c *CircuitBreaker) GetManyByFilter(
+	ctx context.Context,
+	filter interface{},
+	modelBuilder func() Document,
+	opts ...*options.FindOptions,
+) (docs []Document, err error) {
+	err = c.call(ctx, func() error {
+		docs, err = c.Storage.GetManyByFilter(ctx, filter, modelBuilder, opts...)
+		return err
+	})
+
+	return docs, err
+}
+
+// Iterate() does not count errors of fn as failures
+func (c *CircuitBreaker) Iterate(
+	ctx context.Context,
+	filter interface{},
+	modelBuilder func() Document,
+	fn func(Document) error,
+	opts ...*options.FindOptions,
+) error {
+	var (
+		res      error
+		fnFailed bool
+	)
+
+	err := c.call(ctx, func() error {
+		res = c.Storage.Iterate(ctx, filter, modelBuilder, func(m Document) error {
+			err := fn(m)
+			fnFailed = err != nil && !errors.Is(err, ErrStopIteration)
+
+			return err
+		}, opts...)
+		if fnFailed {
+			return nil
+		}
+
+		return res
+	})
+	if fnFailed {
+		return res
+	}
+
+	return err
+}
+
+// Iterator()
+func (c *CircuitBreaker) Iterator(
+	ctx context.Context,
+	filter interface{},
+	modelBuilder func() Document,
+	opts ...*options.FindOptions,
+) (it *Iterator, err error) {
+	err = c.call(ctx, func() error {
+		it, err = c.Storage.Iterator(ctx, filter, modelBuilder, opts...)
+		return err
+	})
+
+	return it, err
+}
+
+// FindAllByFilter()
+func (c *CircuitBreaker) FindAllByFilter(
+	ctx context.Context,
+	filter interface{},
+	docs interface{},
+	opts ...*options.FindOptions,
+) error {
+	return c.call(ctx, func() error {
+		return c.Storage.FindAllByFilter(ctx, filter, docs, opts...)
+	})
+}
+
+// FindManyByFilter()
+func (c *CircuitBreaker) FindManyByFilter(
+	ctx context.Context,
+	filter interface{},
+	opts ...*options.FindOptions,
+) (cur *mongo.Cursor, err error) {
+	err = c.call(ctx, func() error {
+		cur, err = c.Storage.FindManyByFilter(ctx, filter, opts...)
+		return err
+	})
+
+	return cur, err
+}
+
+// CountByFilter()
+func (c *CircuitBreaker) CountByFilter(ctx context.Context, filter interface{}) (count int64, err error) {
+	err = c.call(ctx, func() error {
+		count, err = c.Storage.CountByFilter(ctx, filter)
+		return err
+	})
+
+	return count, err
+}
+
+// DeleteManyByFilter()
+func (c *CircuitBreaker) DeleteManyByFilter(
+	ctx context.Context,
+	filter interface{},
+	opts ...*options.DeleteOptions,
+) (res *mongo.DeleteResult, err error) {
+	err = c.call(ctx, func() error {
+		res, err = c.Storage.DeleteManyByFilter(ctx, filter, opts...)
+		return err
+	})
+
+	return res, err
+}
+
+// DeleteOneByID()
+func (c *CircuitBreaker) DeleteOneByID(ctx context.Context, docID string) error {
+	return c.call(ctx, func() error {
+		return c.Storage.DeleteOneByID(ctx, docID)
+	})
+}
+
+// DeleteOne()
+func (c *CircuitBreaker) DeleteOne(ctx context.Context, m Document) error {
+	return c.call(ctx, func() error {
+		return c.Storage.DeleteOne(ctx, m)
+	})
+}
+
+// DeleteAll()
+func (c *CircuitBreaker) DeleteAll(ctx context.Context) error {
+	return c.call(ctx, func() error {
+		return c.Storage.DeleteAll(ctx)
+	})
+}
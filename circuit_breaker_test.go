@@ -0,0 +1,144 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	timecop "github.com/bluele/go-timecop"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/wajox/mongol"
+)
+
+// flakyStorage fails GetOneByID() and Ping() with configured errors
+type flakyStorage struct {
+	*BaseCollection
+	err     error
+	pingErr error
+	calls   int
+}
+
+func (s *flakyStorage) GetOneByID(ctx context.Context, recordID string, m Document, opts ...*options.FindOneOptions) error {
+	s.calls++
+
+	return s.err
+}
+
+func (s *flakyStorage) Ping(ctx context.Context) error {
+	return s.pingErr
+}
+
+var _ = Describe("CircuitBreaker", func() {
+	var (
+		storage *flakyStorage
+		breaker *CircuitBreaker
+		changes []CircuitState
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		base := newTestCollection("circuit_test")
+
+		storage = &flakyStorage{BaseCollection: base, err: errors.New("connection refused")}
+
+		changes = nil
+
+		breaker = NewCircuitBreaker(storage)
+		breaker.ConsecutiveFailures = 3
+		breaker.OnStateChange = func(from, to CircuitState) {
+			Expect(breaker.State()).To(Equal(to))
+			changes = append(changes, to)
+		}
+
+		now = time.Now()
+		timecop.Freeze(now)
+	})
+
+	AfterEach(func() {
+		timecop.Return()
+	})
+
+	get := func() error {
+		return breaker.GetOneByID(context.TODO(), "id", &ExampleModel{})
+	}
+
+	It("should trip after consecutive failures and fail fast", func() {
+		for i := 0; i < 3; i++ {
+			Expect(get()).To(Equal(storage.err))
+		}
+
+		Expect(breaker.State()).To(Equal(CircuitOpen))
+		Expect(get()).To(Equal(ErrCircuitOpen))
+		Expect(storage.calls).To(Equal(3))
+		Expect(changes).To(Equal([]CircuitState{CircuitOpen}))
+	})
+
+	It("should not count domain errors as failures", func() {
+		storage.err = ErrDocumentNotFound
+
+		for i := 0; i < 10; i++ {
+			Expect(get()).To(Equal(ErrDocumentNotFound))
+		}
+
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+	})
+
+	It("should trip by the failure rate", func() {
+		breaker.ConsecutiveFailures = 0
+		breaker.MinRequests = 4
+		breaker.FailureRate = 0.5
+
+		failure := storage.err
+		for i := 0; i < 4; i++ {
+			if i%2 == 0 {
+				storage.err = nil
+			} else {
+				storage.err = failure
+			}
+
+			get()
+		}
+
+		Expect(breaker.State()).To(Equal(CircuitOpen))
+	})
+
+	It("should probe the storage with Ping()", func() {
+		for i := 0; i < 3; i++ {
+			get()
+		}
+
+		storage.pingErr = errors.New("still down")
+		timecop.Freeze(now.Add(DefaultCircuitOpenTimeout))
+
+		Expect(get()).To(Equal(ErrCircuitOpen))
+		Expect(breaker.State()).To(Equal(CircuitOpen))
+
+		storage.pingErr = nil
+		storage.err = nil
+
+		Expect(get()).To(Equal(ErrCircuitOpen))
+
+		timecop.Freeze(now.Add(DefaultCircuitOpenTimeout * 2))
+
+		Expect(get()).To(BeNil())
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+		Expect(changes).To(Equal([]CircuitState{
+			CircuitOpen,
+			CircuitHalfOpen,
+			CircuitOpen,
+			CircuitHalfOpen,
+			CircuitClosed,
+		}))
+	})
+
+	Describe("CircuitState", func() {
+		It("should have names", func() {
+			Expect(CircuitClosed.String()).To(Equal("closed"))
+			Expect(CircuitOpen.String()).To(Equal("open"))
+			Expect(CircuitHalfOpen.String()).To(Equal("half-open"))
+		})
+	})
+})
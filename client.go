@@ -0,0 +1,263 @@
+package mongol
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	sharedClientsMu sync.Mutex
+	sharedClients   = make(map[string]*Client)
+)
+
+// Client
+type Client struct {
+	mongoClient *mongo.Client
+	registry    *bsoncodec.Registry
+
+	// sharedKey is set for clients created by NewSharedClient()
+	sharedKey string
+
+	mu           sync.Mutex
+	refs         int
+	inflight     int
+	closed       bool
+	disconnected bool
+	drained      chan struct{}
+}
+
+// NewClient() connects to the mongo server with the URI and given ClientOptions
+func NewClient(ctx context.Context, mongoURI string, opts ...ClientOption) (*Client, error) {
+	co := NewClientOptions(mongoURI, opts...)
+
+	c, err := mongo.Connect(ctx, co)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{mongoClient: c, registry: co.Registry, refs: 1}, nil
+}
+
+// NewSharedClient() returns a client shared by all callers with the same URI
+// Every call has to be paired with Close(), the connection is closed by the last one
+func NewSharedClient(ctx context.Context, mongoURI string) (*Client, error) {
+	sharedClientsMu.Lock()
+	defer sharedClientsMu.Unlock()
+
+	if c, ok := sharedClients[mongoURI]; ok && c.acquire() {
+		return c, nil
+	}
+
+	c, err := NewClient(ctx, mongoURI)
+	if err != nil {
+		return nil, err
+	}
+
+	c.sharedKey = mongoURI
+	sharedClients[mongoURI] = c
+
+	return c, nil
+}
+
+// GetMongoClient
+func (c *Client) MongoClient() *mongo.Client {
+	return c.mongoClient
+}
+
+// BSONRegistry() returns the codecs registry set by WithRegistry() or the default one
+func (c *Client) BSONRegistry() *bsoncodec.Registry {
+	if c == nil || c.registry == nil {
+		return bson.DefaultRegistry
+	}
+
+	return c.registry
+}
+
+// Close() releases the client, the connection is closed when the last reference is released
+// in-flight operations are drained before disconnecting
+func (c *Client) Close(ctx context.Context) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+
+	c.refs--
+	last := c.refs <= 0
+	// the last reference closes the client before it can be acquired again
+	c.closed = last
+	c.mu.Unlock()
+
+	if !last {
+		return nil
+	}
+
+	return c.Shutdown(ctx)
+}
+
+// Shutdown() rejects new operations with ErrClientClosed,
+// waits for in-flight operations until ctx is done and disconnects regardless of references
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.forget()
+
+	drainErr := c.Drain(ctx)
+
+	c.mu.Lock()
+	disconnected := c.disconnected
+	c.disconnected = true
+	c.mu.Unlock()
+
+	if disconnected {
+		return drainErr
+	}
+
+	if err := c.mongoClient.Disconnect(ctx); err != nil {
+		return err
+	}
+
+	return drainErr
+}
+
+// Drain() rejects new operations with ErrClientClosed and waits for in-flight operations
+func (c *Client) Drain(ctx context.Context) error {
+	c.mu.Lock()
+	c.closed = true
+
+	if c.inflight == 0 {
+		c.mu.Unlock()
+		return nil
+	}
+
+	if c.drained == nil {
+		c.drained = make(chan struct{})
+	}
+
+	drained := c.drained
+	c.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// acquire() adds a reference to the shared client, false means that the client is already closed
+func (c *Client) acquire() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+
+	c.refs++
+
+	return true
+}
+
+// forget() removes the client from shared clients
+func (c *Client) forget() {
+	if c.sharedKey == "" {
+		return
+	}
+
+	sharedClientsMu.Lock()
+	defer sharedClientsMu.Unlock()
+
+	if sharedClients[c.sharedKey] == c {
+		delete(sharedClients, c.sharedKey)
+	}
+}
+
+// begin() registers an in-flight operation
+func (c *Client) begin() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+
+	c.inflight++
+
+	return nil
+}
+
+// end() unregisters an in-flight operation
+func (c *Client) end() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inflight--
+
+	if c.inflight == 0 && c.drained != nil {
+		close(c.drained)
+		c.drained = nil
+	}
+}
+
+// ShutdownGroup closes many clients during service termination
+type ShutdownGroup struct {
+	mu      sync.Mutex
+	clients []*Client
+}
+
+// NewShutdownGroup() is a constructor for ShutdownGroup struct
+func NewShutdownGroup(clients ...*Client) *ShutdownGroup {
+	return &ShutdownGroup{clients: clients}
+}
+
+// Add() adds the client to the group
+func (g *ShutdownGroup) Add(c *Client) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.clients = append(g.clients, c)
+}
+
+// Shutdown() drains in-flight operations of all clients concurrently and disconnects them
+// the first error is returned
+func (g *ShutdownGroup) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	clients := g.clients
+	g.clients = nil
+	g.mu.Unlock()
+
+	errs := make(chan error, len(clients))
+	wg := sync.WaitGroup{}
+
+	for i := range clients {
+		wg.Add(1)
+
+		go func(c *Client) {
+			defer wg.Done()
+
+			errs <- c.Shutdown(ctx)
+		}(clients[i])
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
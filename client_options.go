@@ -0,0 +1,153 @@
+package mongol
+
+import (
+	"crypto/tls"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// ClientOption configures the mongo client created by NewClient()
+type ClientOption func(o *options.ClientOptions)
+
+// NewClientOptions() builds mongo client options from the URI and given ClientOptions
+func NewClientOptions(mongoURI string, opts ...ClientOption) *options.ClientOptions {
+	o := options.Client().ApplyURI(mongoURI)
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithClientOptions() merges pre-built client options, their non-empty fields take precedence
+func WithClientOptions(co *options.ClientOptions) ClientOption {
+	return func(o *options.ClientOptions) {
+		*o = *options.MergeClientOptions(o, co)
+	}
+}
+
+// WithAppName() sets the application name sent to the server
+func WithAppName(name string) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetAppName(name)
+	}
+}
+
+// WithMaxPoolSize() sets the maximum number of connections per server
+func WithMaxPoolSize(size uint64) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetMaxPoolSize(size)
+	}
+}
+
+// WithMinPoolSize() sets the minimum number of connections per server
+func WithMinPoolSize(size uint64) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetMinPoolSize(size)
+	}
+}
+
+// WithMaxConnIdleTime() sets how long a connection can stay idle in the pool
+func WithMaxConnIdleTime(d time.Duration) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetMaxConnIdleTime(d)
+	}
+}
+
+// WithConnectTimeout() sets the timeout of establishing a connection
+func WithConnectTimeout(d time.Duration) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetConnectTimeout(d)
+	}
+}
+
+// WithServerSelectionTimeout() sets how long the driver waits for a suitable server
+func WithServerSelectionTimeout(d time.Duration) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetServerSelectionTimeout(d)
+	}
+}
+
+// WithCompressors() sets the compressors for the wire protocol, e.g. "snappy", "zlib", "zstd"
+func WithCompressors(compressors ...string) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetCompressors(compressors)
+	}
+}
+
+// WithReadConcern() sets the default read concern
+func WithReadConcern(rc *readconcern.ReadConcern) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetReadConcern(rc)
+	}
+}
+
+// WithWriteConcern() sets the default write concern
+func WithWriteConcern(wc *writeconcern.WriteConcern) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetWriteConcern(wc)
+	}
+}
+
+// WithReadPreference() sets the default read preference
+func WithReadPreference(rp *readpref.ReadPref) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetReadPreference(rp)
+	}
+}
+
+// WithRetryReads() enables or disables retryable reads
+func WithRetryReads(retry bool) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetRetryReads(retry)
+	}
+}
+
+// WithRetryWrites() enables or disables retryable writes
+func WithRetryWrites(retry bool) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetRetryWrites(retry)
+	}
+}
+
+// WithTLSConfig() sets the TLS configuration of connections
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetTLSConfig(cfg)
+	}
+}
+
+// WithRegistry() sets a custom BSON codecs registry
+func WithRegistry(r *bsoncodec.Registry) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetRegistry(r)
+	}
+}
+
+// WithServerMonitor() sets the monitor of server and topology events
+func WithServerMonitor(m *event.ServerMonitor) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetServerMonitor(m)
+	}
+}
+
+// WithCommandMonitor() sets the monitor of commands
+func WithCommandMonitor(m *event.CommandMonitor) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetMonitor(m)
+	}
+}
+
+// WithPoolMonitor() sets the monitor of connection pool events
+func WithPoolMonitor(m *event.PoolMonitor) ClientOption {
+	return func(o *options.ClientOptions) {
+		o.SetPoolMonitor(m)
+	}
+}
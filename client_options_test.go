@@ -0,0 +1,82 @@
+package mongol_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+
+	"github.com/wajox/mongol"
+)
+
+var _ = Describe("ClientOption", func() {
+	Describe("NewClientOptions()", func() {
+		It("should apply given options", func() {
+			monitor := &event.CommandMonitor{}
+
+			o := mongol.NewClientOptions(
+				"mongodb://0.0.0.0:27017",
+				mongol.WithAppName("app"),
+				mongol.WithMaxPoolSize(50),
+				mongol.WithMinPoolSize(5),
+				mongol.WithCompressors("zstd"),
+				mongol.WithReadConcern(readconcern.Majority()),
+				mongol.WithWriteConcern(writeconcern.New(writeconcern.WMajority())),
+				mongol.WithReadPreference(readpref.SecondaryPreferred()),
+				mongol.WithRetryWrites(false),
+				mongol.WithServerSelectionTimeout(time.Second),
+				mongol.WithCommandMonitor(monitor),
+			)
+
+			Expect(o.Validate()).To(BeNil())
+			Expect(*o.AppName).To(Equal("app"))
+			Expect(*o.MaxPoolSize).To(Equal(uint64(50)))
+			Expect(*o.MinPoolSize).To(Equal(uint64(5)))
+			Expect(o.Compressors).To(Equal([]string{"zstd"}))
+			Expect(o.ReadConcern).To(Equal(readconcern.Majority()))
+			Expect(o.ReadPreference.Mode()).To(Equal(readpref.SecondaryPreferredMode))
+			Expect(*o.RetryWrites).To(BeFalse())
+			Expect(*o.ServerSelectionTimeout).To(Equal(time.Second))
+			Expect(o.Monitor).To(Equal(monitor))
+		})
+
+		It("should merge pre-built client options", func() {
+			o := mongol.NewClientOptions(
+				"mongodb://0.0.0.0:27017",
+				mongol.WithAppName("app"),
+				mongol.WithClientOptions(options.Client().SetMaxPoolSize(10)),
+			)
+
+			Expect(*o.AppName).To(Equal("app"))
+			Expect(*o.MaxPoolSize).To(Equal(uint64(10)))
+		})
+	})
+
+	Describe("NewClient()", func() {
+		It("should create a client with options", func() {
+			client, err := mongol.NewClient(context.TODO(), "mongodb://0.0.0.0:27017", mongol.WithAppName("app"))
+
+			Expect(err).To(BeNil())
+			Expect(client.MongoClient()).NotTo(BeNil())
+			Expect(client.BSONRegistry()).To(Equal(bson.DefaultRegistry))
+		})
+
+		It("should keep the registry for marshaling documents", func() {
+			registry := bson.NewRegistryBuilder().Build()
+
+			client, err := mongol.NewClient(context.TODO(), "mongodb://0.0.0.0:27017", mongol.WithRegistry(registry))
+			Expect(err).To(BeNil())
+
+			storage := mongol.NewBaseCollectionWithClient(client, "base_models_db_test", "client_options_test")
+			Expect(storage.BSONRegistry()).To(BeIdenticalTo(registry))
+			Expect(mongol.NewCachedCollection(storage, mongol.NewLRUCache(1), time.Minute).BSONRegistry()).To(BeIdenticalTo(registry))
+		})
+	})
+})
@@ -0,0 +1,199 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("Client", func() {
+	mongoURI := "mongodb://0.0.0.0:27017/?appName=client_test"
+
+	Describe("NewSharedClient()", func() {
+		It("should share the client until the last reference is closed", func() {
+			c1, err := NewSharedClient(context.TODO(), mongoURI)
+			Expect(err).To(BeNil())
+
+			c2, err := NewSharedClient(context.TODO(), mongoURI)
+			Expect(err).To(BeNil())
+			Expect(c2).To(BeIdenticalTo(c1))
+
+			storage := NewBaseCollectionWithClient(c1, "base_models_db_test", "client_test")
+
+			Expect(c1.Close(context.TODO())).To(BeNil())
+			Expect(errors.Is(storage.DeleteOneByID(context.TODO(), "123"), ErrInvalidObjectID)).To(BeTrue())
+
+			Expect(c2.Close(context.TODO())).To(BeNil())
+			Expect(errors.Is(storage.DeleteOneByID(context.TODO(), "123"), ErrClientClosed)).To(BeTrue())
+
+			c3, err := NewSharedClient(context.TODO(), mongoURI)
+			Expect(err).To(BeNil())
+			Expect(c3).NotTo(BeIdenticalTo(c1))
+			Expect(c3.Close(context.TODO())).To(BeNil())
+		})
+	})
+
+	Describe("NewBaseCollection()", func() {
+		It("should share the client of collections with the same URI", func() {
+			s1, err := NewBaseCollection(context.TODO(), mongoURI, "base_models_db_test", "client_test")
+			Expect(err).To(BeNil())
+
+			s2, err := NewBaseCollection(context.TODO(), mongoURI, "base_models_db_test", "client_test")
+			Expect(err).To(BeNil())
+
+			Expect(s2.Client).To(BeIdenticalTo(s1.Client))
+			Expect(s1.Close(context.TODO())).To(BeNil())
+			Expect(s1.Close(context.TODO())).To(BeNil())
+			Expect(errors.Is(s2.DeleteOneByID(context.TODO(), "123"), ErrInvalidObjectID)).To(BeTrue())
+			Expect(s2.Close(context.TODO())).To(BeNil())
+		})
+	})
+
+	Describe("BaseCollection.Close()", func() {
+		It("should not close clients passed to NewBaseCollectionWithClient()", func() {
+			client, err := NewClient(context.TODO(), mongoURI)
+			Expect(err).To(BeNil())
+
+			defer client.Shutdown(context.TODO())
+
+			s1 := NewBaseCollectionWithClient(client, "base_models_db_test", "client_test")
+			s2 := NewBaseCollectionWithClient(client, "base_models_db_test", "client_test")
+
+			Expect(s1.Close(context.TODO())).To(BeNil())
+			Expect(errors.Is(s2.DeleteOneByID(context.TODO(), "123"), ErrInvalidObjectID)).To(BeTrue())
+		})
+	})
+
+	Describe("Drain()", func() {
+		for _, c := range []struct {
+			outer, inner string
+			call         func(s *BaseCollection) error
+		}{
+			{UpdateOneMethod, UpdateManyByFilterMethod, func(s *BaseCollection) error {
+				return s.UpdateOne(context.TODO(), NewExampleModel())
+			}},
+			{UpdateOneWithResultMethod, UpdateManyByFilterWithResultMethod, func(s *BaseCollection) error {
+				_, err := s.UpdateOneWithResult(context.TODO(), NewExampleModel())
+				return err
+			}},
+		} {
+			c := c
+
+			It("should let nested calls of in-flight "+c.outer+" finish", func() {
+				client, err := NewClient(context.TODO(), mongoURI)
+				Expect(err).To(BeNil())
+
+				defer client.Shutdown(context.TODO())
+
+				started := make(chan struct{})
+				release := make(chan struct{})
+				errNested := errors.New("nested call")
+
+				storage := NewBaseCollectionWithClient(client, "base_models_db_test", "client_test")
+				storage.AddBeforeHook(c.outer, func(context.Context) error {
+					close(started)
+					<-release
+
+					return nil
+				})
+				// the outer method calls the inner one, the hook stops it before the request
+				storage.AddBeforeHook(c.inner, func(context.Context) error {
+					return errNested
+				})
+
+				updated := make(chan error)
+
+				go func() {
+					updated <- c.call(storage)
+				}()
+
+				<-started
+
+				drained := make(chan error)
+
+				go func() {
+					drained <- client.Drain(context.TODO())
+				}()
+
+				Consistently(drained, 50*time.Millisecond).ShouldNot(Receive())
+				close(release)
+
+				var updateErr error
+				Eventually(updated).Should(Receive(&updateErr))
+				Expect(errors.Is(updateErr, errNested)).To(BeTrue())
+
+				var opErr *OperationError
+				Expect(errors.As(updateErr, &opErr)).To(BeTrue())
+				Expect(opErr.Method).To(Equal(c.outer))
+				Expect(errors.As(opErr.Err, &opErr)).To(BeFalse())
+
+				Eventually(drained).Should(Receive(BeNil()))
+
+				Expect(errors.Is(c.call(storage), ErrClientClosed)).To(BeTrue())
+			})
+		}
+	})
+
+	Describe("ShutdownGroup", func() {
+		var (
+			client  *Client
+			storage *BaseCollection
+			started chan struct{}
+			release chan struct{}
+		)
+
+		BeforeEach(func() {
+			var err error
+			client, err = NewClient(context.TODO(), mongoURI)
+			Expect(err).To(BeNil())
+
+			started = make(chan struct{})
+			release = make(chan struct{})
+
+			storage = NewBaseCollectionWithClient(client, "base_models_db_test", "client_test")
+			storage.AddBeforeHook(DeleteOneByIDMethod, func(context.Context) error {
+				close(started)
+				<-release
+
+				return nil
+			})
+
+			go func() {
+				defer GinkgoRecover()
+
+				Expect(errors.Is(storage.DeleteOneByID(context.TODO(), "123"), ErrInvalidObjectID)).To(BeTrue())
+			}()
+
+			<-started
+		})
+
+		It("should wait for in-flight operations", func() {
+			done := make(chan error)
+
+			go func() {
+				done <- NewShutdownGroup(client).Shutdown(context.TODO())
+			}()
+
+			Consistently(done, 50*time.Millisecond).ShouldNot(Receive())
+			close(release)
+			Eventually(done).Should(Receive(BeNil()))
+
+			Expect(errors.Is(storage.DeleteOneByID(context.TODO(), "123"), ErrClientClosed)).To(BeTrue())
+		})
+
+		It("should stop waiting when the context is done", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			err := NewShutdownGroup(client).Shutdown(ctx)
+			close(release)
+
+			Expect(err).To(Equal(context.DeadlineExceeded))
+		})
+	})
+})
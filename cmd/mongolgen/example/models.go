@@ -0,0 +1,36 @@
+// Package example contains models for mongolgen, models_mongol.go is generated
+package example
+
+import (
+	"time"
+
+	"github.com/wajox/mongol"
+)
+
+//go:generate go run .. -type User,OrderItem
+
+// User
+type User struct {
+	mongol.BaseDocument `bson:",inline" mongol:"collection=people"`
+
+	Email     string            `bson:"email" mongol:"unique"`
+	Name      string            `bson:"name,omitempty"`
+	Age       int               `bson:"age,omitempty" mongol:"index,desc,sparse"`
+	Tags      []string          `bson:"tags,omitempty"`
+	LastLogin *time.Time        `bson:"last_login,omitempty"`
+	Settings  map[string]string `bson:"settings,omitempty"`
+	Address   struct {
+		City string `bson:"city"`
+	} `bson:"address"`
+	Password string `bson:"-"`
+	internal string
+}
+
+// OrderItem
+type OrderItem struct {
+	mongol.BaseDocument `bson:",inline"`
+
+	UserID   string  `bson:"user_id" mongol:"index"`
+	Price    float64 `bson:"price"`
+	Quantity int64
+}
@@ -0,0 +1,862 @@
+// Code generated by mongolgen. DO NOT EDIT.
+
+package example
+
+import (
+	"context"
+	"time"
+
+	"github.com/wajox/mongol"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UserCollectionName is the collection of User documents
+const UserCollectionName = "people"
+
+// BSON keys of User fields
+const (
+	UserFieldID        = "_id"
+	UserFieldCreatedAt = "created_at"
+	UserFieldUpdatedAt = "updated_at"
+	UserFieldEmail     = "email"
+	UserFieldName      = "name"
+	UserFieldAge       = "age"
+	UserFieldTags      = "tags"
+	UserFieldLastLogin = "last_login"
+	UserFieldSettings  = "settings"
+	UserFieldAddress   = "address"
+)
+
+// UserIndexes() returns indexes declared by mongol tags of User fields
+func UserIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: UserFieldEmail, Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: UserFieldAge, Value: -1}},
+			Options: options.Index().SetSparse(true),
+		},
+	}
+}
+
+// UserCollection() returns the definition of User collection for mongol.Registry
+func UserCollection() mongol.CollectionDefinition {
+	return mongol.CollectionDefinition{
+		Name:    UserCollectionName,
+		Model:   &User{},
+		Indexes: UserIndexes(),
+	}
+}
+
+// UserRepository is a typed repository of User documents
+type UserRepository struct {
+	mongol.Storage
+}
+
+// NewUserRepository() is a constructor for UserRepository struct
+func NewUserRepository(s mongol.Storage) *UserRepository {
+	return &UserRepository{Storage: s}
+}
+
+// GetByID() returns User by ID
+func (r *UserRepository) GetByID(ctx context.Context, id string, opts ...*options.FindOneOptions) (*User, error) {
+	m := &User{}
+	if err := r.GetOneByID(ctx, id, m, opts...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// GetOne() returns User matching the filter
+func (r *UserRepository) GetOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) (*User, error) {
+	m := &User{}
+	if err := r.GetOneByFilter(ctx, filter, m, opts...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Find() returns all User documents matching the filter
+func (r *UserRepository) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) ([]*User, error) {
+	docs := []*User{}
+	if err := r.FindAllByFilter(ctx, filter, &docs, opts...); err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// Insert() inserts User and returns its ID
+func (r *UserRepository) Insert(ctx context.Context, m *User, opts ...*options.InsertOneOptions) (string, error) {
+	return r.InsertOne(ctx, m, opts...)
+}
+
+// Update() updates User by its ID
+func (r *UserRepository) Update(ctx context.Context, m *User, opts ...*options.UpdateOptions) error {
+	return r.UpdateOne(ctx, m, opts...)
+}
+
+// Delete() deletes User by ID
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	return r.DeleteOneByID(ctx, id)
+}
+
+// Count() returns the number of User documents matching the filter
+func (r *UserRepository) Count(ctx context.Context, filter interface{}) (int64, error) {
+	return r.CountByFilter(ctx, filter)
+}
+
+// EnsureIndexes() creates indexes declared by mongol tags of User fields
+func (r *UserRepository) EnsureIndexes(ctx context.Context) error {
+	for _, index := range UserIndexes() {
+		if _, err := r.CreateIndex(ctx, index.Keys, index.Options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UserFilterBuilder builds filters of User documents with typed values
+type UserFilterBuilder struct {
+	*mongol.FilterBuilder
+}
+
+// UserFilter() initializes a new UserFilterBuilder
+func UserFilter() *UserFilterBuilder {
+	return &UserFilterBuilder{FilterBuilder: mongol.NewFilterBuilder()}
+}
+
+// IDEq() implements $eq condition for ID
+func (f *UserFilterBuilder) IDEq(value primitive.ObjectID) *UserFilterBuilder {
+	f.EqualTo(UserFieldID, value)
+
+	return f
+}
+
+// IDNe() implements $ne condition for ID
+func (f *UserFilterBuilder) IDNe(value primitive.ObjectID) *UserFilterBuilder {
+	f.NotEqualTo(UserFieldID, value)
+
+	return f
+}
+
+// IDIn() implements $in condition for ID
+func (f *UserFilterBuilder) IDIn(values ...primitive.ObjectID) *UserFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(UserFieldID, a)
+
+	return f
+}
+
+// CreatedAtEq() implements $eq condition for CreatedAt
+func (f *UserFilterBuilder) CreatedAtEq(value time.Time) *UserFilterBuilder {
+	f.EqualTo(UserFieldCreatedAt, value)
+
+	return f
+}
+
+// CreatedAtNe() implements $ne condition for CreatedAt
+func (f *UserFilterBuilder) CreatedAtNe(value time.Time) *UserFilterBuilder {
+	f.NotEqualTo(UserFieldCreatedAt, value)
+
+	return f
+}
+
+// CreatedAtIn() implements $in condition for CreatedAt
+func (f *UserFilterBuilder) CreatedAtIn(values ...time.Time) *UserFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(UserFieldCreatedAt, a)
+
+	return f
+}
+
+// CreatedAtGt() implements $gt condition for CreatedAt
+func (f *UserFilterBuilder) CreatedAtGt(value time.Time) *UserFilterBuilder {
+	f.Gt(UserFieldCreatedAt, value)
+
+	return f
+}
+
+// CreatedAtGte() implements $gte condition for CreatedAt
+func (f *UserFilterBuilder) CreatedAtGte(value time.Time) *UserFilterBuilder {
+	f.Gte(UserFieldCreatedAt, value)
+
+	return f
+}
+
+// CreatedAtLt() implements $lt condition for CreatedAt
+func (f *UserFilterBuilder) CreatedAtLt(value time.Time) *UserFilterBuilder {
+	f.Lt(UserFieldCreatedAt, value)
+
+	return f
+}
+
+// CreatedAtLte() implements $lte condition for CreatedAt
+func (f *UserFilterBuilder) CreatedAtLte(value time.Time) *UserFilterBuilder {
+	f.Lte(UserFieldCreatedAt, value)
+
+	return f
+}
+
+// UpdatedAtEq() implements $eq condition for UpdatedAt
+func (f *UserFilterBuilder) UpdatedAtEq(value time.Time) *UserFilterBuilder {
+	f.EqualTo(UserFieldUpdatedAt, value)
+
+	return f
+}
+
+// UpdatedAtNe() implements $ne condition for UpdatedAt
+func (f *UserFilterBuilder) UpdatedAtNe(value time.Time) *UserFilterBuilder {
+	f.NotEqualTo(UserFieldUpdatedAt, value)
+
+	return f
+}
+
+// UpdatedAtIn() implements $in condition for UpdatedAt
+func (f *UserFilterBuilder) UpdatedAtIn(values ...time.Time) *UserFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(UserFieldUpdatedAt, a)
+
+	return f
+}
+
+// UpdatedAtGt() implements $gt condition for UpdatedAt
+func (f *UserFilterBuilder) UpdatedAtGt(value time.Time) *UserFilterBuilder {
+	f.Gt(UserFieldUpdatedAt, value)
+
+	return f
+}
+
+// UpdatedAtGte() implements $gte condition for UpdatedAt
+func (f *UserFilterBuilder) UpdatedAtGte(value time.Time) *UserFilterBuilder {
+	f.Gte(UserFieldUpdatedAt, value)
+
+	return f
+}
+
+// UpdatedAtLt() implements $lt condition for UpdatedAt
+func (f *UserFilterBuilder) UpdatedAtLt(value time.Time) *UserFilterBuilder {
+	f.Lt(UserFieldUpdatedAt, value)
+
+	return f
+}
+
+// UpdatedAtLte() implements $lte condition for UpdatedAt
+func (f *UserFilterBuilder) UpdatedAtLte(value time.Time) *UserFilterBuilder {
+	f.Lte(UserFieldUpdatedAt, value)
+
+	return f
+}
+
+// EmailEq() implements $eq condition for Email
+func (f *UserFilterBuilder) EmailEq(value string) *UserFilterBuilder {
+	f.EqualTo(UserFieldEmail, value)
+
+	return f
+}
+
+// EmailNe() implements $ne condition for Email
+func (f *UserFilterBuilder) EmailNe(value string) *UserFilterBuilder {
+	f.NotEqualTo(UserFieldEmail, value)
+
+	return f
+}
+
+// EmailIn() implements $in condition for Email
+func (f *UserFilterBuilder) EmailIn(values ...string) *UserFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(UserFieldEmail, a)
+
+	return f
+}
+
+// NameEq() implements $eq condition for Name
+func (f *UserFilterBuilder) NameEq(value string) *UserFilterBuilder {
+	f.EqualTo(UserFieldName, value)
+
+	return f
+}
+
+// NameNe() implements $ne condition for Name
+func (f *UserFilterBuilder) NameNe(value string) *UserFilterBuilder {
+	f.NotEqualTo(UserFieldName, value)
+
+	return f
+}
+
+// NameIn() implements $in condition for Name
+func (f *UserFilterBuilder) NameIn(values ...string) *UserFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(UserFieldName, a)
+
+	return f
+}
+
+// AgeEq() implements $eq condition for Age
+func (f *UserFilterBuilder) AgeEq(value int) *UserFilterBuilder {
+	f.EqualTo(UserFieldAge, value)
+
+	return f
+}
+
+// AgeNe() implements $ne condition for Age
+func (f *UserFilterBuilder) AgeNe(value int) *UserFilterBuilder {
+	f.NotEqualTo(UserFieldAge, value)
+
+	return f
+}
+
+// AgeIn() implements $in condition for Age
+func (f *UserFilterBuilder) AgeIn(values ...int) *UserFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(UserFieldAge, a)
+
+	return f
+}
+
+// AgeGt() implements $gt condition for Age
+func (f *UserFilterBuilder) AgeGt(value int) *UserFilterBuilder {
+	f.Gt(UserFieldAge, value)
+
+	return f
+}
+
+// AgeGte() implements $gte condition for Age
+func (f *UserFilterBuilder) AgeGte(value int) *UserFilterBuilder {
+	f.Gte(UserFieldAge, value)
+
+	return f
+}
+
+// AgeLt() implements $lt condition for Age
+func (f *UserFilterBuilder) AgeLt(value int) *UserFilterBuilder {
+	f.Lt(UserFieldAge, value)
+
+	return f
+}
+
+// AgeLte() implements $lte condition for Age
+func (f *UserFilterBuilder) AgeLte(value int) *UserFilterBuilder {
+	f.Lte(UserFieldAge, value)
+
+	return f
+}
+
+// TagsEq() implements $eq condition for Tags
+func (f *UserFilterBuilder) TagsEq(value []string) *UserFilterBuilder {
+	f.EqualTo(UserFieldTags, value)
+
+	return f
+}
+
+// TagsNe() implements $ne condition for Tags
+func (f *UserFilterBuilder) TagsNe(value []string) *UserFilterBuilder {
+	f.NotEqualTo(UserFieldTags, value)
+
+	return f
+}
+
+// TagsIn() implements $in condition for Tags
+func (f *UserFilterBuilder) TagsIn(values ...[]string) *UserFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(UserFieldTags, a)
+
+	return f
+}
+
+// LastLoginEq() implements $eq condition for LastLogin
+func (f *UserFilterBuilder) LastLoginEq(value *time.Time) *UserFilterBuilder {
+	f.EqualTo(UserFieldLastLogin, value)
+
+	return f
+}
+
+// LastLoginNe() implements $ne condition for LastLogin
+func (f *UserFilterBuilder) LastLoginNe(value *time.Time) *UserFilterBuilder {
+	f.NotEqualTo(UserFieldLastLogin, value)
+
+	return f
+}
+
+// LastLoginIn() implements $in condition for LastLogin
+func (f *UserFilterBuilder) LastLoginIn(values ...*time.Time) *UserFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(UserFieldLastLogin, a)
+
+	return f
+}
+
+// LastLoginGt() implements $gt condition for LastLogin
+func (f *UserFilterBuilder) LastLoginGt(value *time.Time) *UserFilterBuilder {
+	f.Gt(UserFieldLastLogin, value)
+
+	return f
+}
+
+// LastLoginGte() implements $gte condition for LastLogin
+func (f *UserFilterBuilder) LastLoginGte(value *time.Time) *UserFilterBuilder {
+	f.Gte(UserFieldLastLogin, value)
+
+	return f
+}
+
+// LastLoginLt() implements $lt condition for LastLogin
+func (f *UserFilterBuilder) LastLoginLt(value *time.Time) *UserFilterBuilder {
+	f.Lt(UserFieldLastLogin, value)
+
+	return f
+}
+
+// LastLoginLte() implements $lte condition for LastLogin
+func (f *UserFilterBuilder) LastLoginLte(value *time.Time) *UserFilterBuilder {
+	f.Lte(UserFieldLastLogin, value)
+
+	return f
+}
+
+// SettingsEq() implements $eq condition for Settings
+func (f *UserFilterBuilder) SettingsEq(value map[string]string) *UserFilterBuilder {
+	f.EqualTo(UserFieldSettings, value)
+
+	return f
+}
+
+// SettingsNe() implements $ne condition for Settings
+func (f *UserFilterBuilder) SettingsNe(value map[string]string) *UserFilterBuilder {
+	f.NotEqualTo(UserFieldSettings, value)
+
+	return f
+}
+
+// SettingsIn() implements $in condition for Settings
+func (f *UserFilterBuilder) SettingsIn(values ...map[string]string) *UserFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(UserFieldSettings, a)
+
+	return f
+}
+
+// OrderItemCollectionName is the collection of OrderItem documents
+const OrderItemCollectionName = "order_items"
+
+// BSON keys of OrderItem fields
+const (
+	OrderItemFieldID        = "_id"
+	OrderItemFieldCreatedAt = "created_at"
+	OrderItemFieldUpdatedAt = "updated_at"
+	OrderItemFieldUserID    = "user_id"
+	OrderItemFieldPrice     = "price"
+	OrderItemFieldQuantity  = "quantity"
+)
+
+// OrderItemIndexes() returns indexes declared by mongol tags of OrderItem fields
+func OrderItemIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: OrderItemFieldUserID, Value: 1}},
+			Options: options.Index(),
+		},
+	}
+}
+
+// OrderItemCollection() returns the definition of OrderItem collection for mongol.Registry
+func OrderItemCollection() mongol.CollectionDefinition {
+	return mongol.CollectionDefinition{
+		Name:    OrderItemCollectionName,
+		Model:   &OrderItem{},
+		Indexes: OrderItemIndexes(),
+	}
+}
+
+// OrderItemRepository is a typed repository of OrderItem documents
+type OrderItemRepository struct {
+	mongol.Storage
+}
+
+// NewOrderItemRepository() is a constructor for OrderItemRepository struct
+func NewOrderItemRepository(s mongol.Storage) *OrderItemRepository {
+	return &OrderItemRepository{Storage: s}
+}
+
+// GetByID() returns OrderItem by ID
+func (r *OrderItemRepository) GetByID(ctx context.Context, id string, opts ...*options.FindOneOptions) (*OrderItem, error) {
+	m := &OrderItem{}
+	if err := r.GetOneByID(ctx, id, m, opts...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// GetOne() returns OrderItem matching the filter
+func (r *OrderItemRepository) GetOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) (*OrderItem, error) {
+	m := &OrderItem{}
+	if err := r.GetOneByFilter(ctx, filter, m, opts...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Find() returns all OrderItem documents matching the filter
+func (r *OrderItemRepository) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) ([]*OrderItem, error) {
+	docs := []*OrderItem{}
+	if err := r.FindAllByFilter(ctx, filter, &docs, opts...); err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// Insert() inserts OrderItem and returns its ID
+func (r *OrderItemRepository) Insert(ctx context.Context, m *OrderItem, opts ...*options.InsertOneOptions) (string, error) {
+	return r.InsertOne(ctx, m, opts...)
+}
+
+// Update() updates OrderItem by its ID
+func (r *OrderItemRepository) Update(ctx context.Context, m *OrderItem, opts ...*options.UpdateOptions) error {
+	return r.UpdateOne(ctx, m, opts...)
+}
+
+// Delete() deletes OrderItem by ID
+func (r *OrderItemRepository) Delete(ctx context.Context, id string) error {
+	return r.DeleteOneByID(ctx, id)
+}
+
+// Count() returns the number of OrderItem documents matching the filter
+func (r *OrderItemRepository) Count(ctx context.Context, filter interface{}) (int64, error) {
+	return r.CountByFilter(ctx, filter)
+}
+
+// EnsureIndexes() creates indexes declared by mongol tags of OrderItem fields
+func (r *OrderItemRepository) EnsureIndexes(ctx context.Context) error {
+	for _, index := range OrderItemIndexes() {
+		if _, err := r.CreateIndex(ctx, index.Keys, index.Options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OrderItemFilterBuilder builds filters of OrderItem documents with typed values
+type OrderItemFilterBuilder struct {
+	*mongol.FilterBuilder
+}
+
+// OrderItemFilter() initializes a new OrderItemFilterBuilder
+func OrderItemFilter() *OrderItemFilterBuilder {
+	return &OrderItemFilterBuilder{FilterBuilder: mongol.NewFilterBuilder()}
+}
+
+// IDEq() implements $eq condition for ID
+func (f *OrderItemFilterBuilder) IDEq(value primitive.ObjectID) *OrderItemFilterBuilder {
+	f.EqualTo(OrderItemFieldID, value)
+
+	return f
+}
+
+// IDNe() implements $ne condition for ID
+func (f *OrderItemFilterBuilder) IDNe(value primitive.ObjectID) *OrderItemFilterBuilder {
+	f.NotEqualTo(OrderItemFieldID, value)
+
+	return f
+}
+
+// IDIn() implements $in condition for ID
+func (f *OrderItemFilterBuilder) IDIn(values ...primitive.ObjectID) *OrderItemFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(OrderItemFieldID, a)
+
+	return f
+}
+
+// CreatedAtEq() implements $eq condition for CreatedAt
+func (f *OrderItemFilterBuilder) CreatedAtEq(value time.Time) *OrderItemFilterBuilder {
+	f.EqualTo(OrderItemFieldCreatedAt, value)
+
+	return f
+}
+
+// CreatedAtNe() implements $ne condition for CreatedAt
+func (f *OrderItemFilterBuilder) CreatedAtNe(value time.Time) *OrderItemFilterBuilder {
+	f.NotEqualTo(OrderItemFieldCreatedAt, value)
+
+	return f
+}
+
+// CreatedAtIn() implements $in condition for CreatedAt
+func (f *OrderItemFilterBuilder) CreatedAtIn(values ...time.Time) *OrderItemFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(OrderItemFieldCreatedAt, a)
+
+	return f
+}
+
+// CreatedAtGt() implements $gt condition for CreatedAt
+func (f *OrderItemFilterBuilder) CreatedAtGt(value time.Time) *OrderItemFilterBuilder {
+	f.Gt(OrderItemFieldCreatedAt, value)
+
+	return f
+}
+
+// CreatedAtGte() implements $gte condition for CreatedAt
+func (f *OrderItemFilterBuilder) CreatedAtGte(value time.Time) *OrderItemFilterBuilder {
+	f.Gte(OrderItemFieldCreatedAt, value)
+
+	return f
+}
+
+// CreatedAtLt() implements $lt condition for CreatedAt
+func (f *OrderItemFilterBuilder) CreatedAtLt(value time.Time) *OrderItemFilterBuilder {
+	f.Lt(OrderItemFieldCreatedAt, value)
+
+	return f
+}
+
+// CreatedAtLte() implements $lte condition for CreatedAt
+func (f *OrderItemFilterBuilder) CreatedAtLte(value time.Time) *OrderItemFilterBuilder {
+	f.Lte(OrderItemFieldCreatedAt, value)
+
+	return f
+}
+
+// UpdatedAtEq() implements $eq condition for UpdatedAt
+func (f *OrderItemFilterBuilder) UpdatedAtEq(value time.Time) *OrderItemFilterBuilder {
+	f.EqualTo(OrderItemFieldUpdatedAt, value)
+
+	return f
+}
+
+// UpdatedAtNe() implements $ne condition for UpdatedAt
+func (f *OrderItemFilterBuilder) UpdatedAtNe(value time.Time) *OrderItemFilterBuilder {
+	f.NotEqualTo(OrderItemFieldUpdatedAt, value)
+
+	return f
+}
+
+// UpdatedAtIn() implements $in condition for UpdatedAt
+func (f *OrderItemFilterBuilder) UpdatedAtIn(values ...time.Time) *OrderItemFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(OrderItemFieldUpdatedAt, a)
+
+	return f
+}
+
+// UpdatedAtGt() implements $gt condition for UpdatedAt
+func (f *OrderItemFilterBuilder) UpdatedAtGt(value time.Time) *OrderItemFilterBuilder {
+	f.Gt(OrderItemFieldUpdatedAt, value)
+
+	return f
+}
+
+// UpdatedAtGte() implements $gte condition for UpdatedAt
+func (f *OrderItemFilterBuilder) UpdatedAtGte(value time.Time) *OrderItemFilterBuilder {
+	f.Gte(OrderItemFieldUpdatedAt, value)
+
+	return f
+}
+
+// UpdatedAtLt() implements $lt condition for UpdatedAt
+func (f *OrderItemFilterBuilder) UpdatedAtLt(value time.Time) *OrderItemFilterBuilder {
+	f.Lt(OrderItemFieldUpdatedAt, value)
+
+	return f
+}
+
+// UpdatedAtLte() implements $lte condition for UpdatedAt
+func (f *OrderItemFilterBuilder) UpdatedAtLte(value time.Time) *OrderItemFilterBuilder {
+	f.Lte(OrderItemFieldUpdatedAt, value)
+
+	return f
+}
+
+// UserIDEq() implements $eq condition for UserID
+func (f *OrderItemFilterBuilder) UserIDEq(value string) *OrderItemFilterBuilder {
+	f.EqualTo(OrderItemFieldUserID, value)
+
+	return f
+}
+
+// UserIDNe() implements $ne condition for UserID
+func (f *OrderItemFilterBuilder) UserIDNe(value string) *OrderItemFilterBuilder {
+	f.NotEqualTo(OrderItemFieldUserID, value)
+
+	return f
+}
+
+// UserIDIn() implements $in condition for UserID
+func (f *OrderItemFilterBuilder) UserIDIn(values ...string) *OrderItemFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(OrderItemFieldUserID, a)
+
+	return f
+}
+
+// PriceEq() implements $eq condition for Price
+func (f *OrderItemFilterBuilder) PriceEq(value float64) *OrderItemFilterBuilder {
+	f.EqualTo(OrderItemFieldPrice, value)
+
+	return f
+}
+
+// PriceNe() implements $ne condition for Price
+func (f *OrderItemFilterBuilder) PriceNe(value float64) *OrderItemFilterBuilder {
+	f.NotEqualTo(OrderItemFieldPrice, value)
+
+	return f
+}
+
+// PriceIn() implements $in condition for Price
+func (f *OrderItemFilterBuilder) PriceIn(values ...float64) *OrderItemFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(OrderItemFieldPrice, a)
+
+	return f
+}
+
+// PriceGt() implements $gt condition for Price
+func (f *OrderItemFilterBuilder) PriceGt(value float64) *OrderItemFilterBuilder {
+	f.Gt(OrderItemFieldPrice, value)
+
+	return f
+}
+
+// PriceGte() implements $gte condition for Price
+func (f *OrderItemFilterBuilder) PriceGte(value float64) *OrderItemFilterBuilder {
+	f.Gte(OrderItemFieldPrice, value)
+
+	return f
+}
+
+// PriceLt() implements $lt condition for Price
+func (f *OrderItemFilterBuilder) PriceLt(value float64) *OrderItemFilterBuilder {
+	f.Lt(OrderItemFieldPrice, value)
+
+	return f
+}
+
+// PriceLte() implements $lte condition for Price
+func (f *OrderItemFilterBuilder) PriceLte(value float64) *OrderItemFilterBuilder {
+	f.Lte(OrderItemFieldPrice, value)
+
+	return f
+}
+
+// QuantityEq() implements $eq condition for Quantity
+func (f *OrderItemFilterBuilder) QuantityEq(value int64) *OrderItemFilterBuilder {
+	f.EqualTo(OrderItemFieldQuantity, value)
+
+	return f
+}
+
+// QuantityNe() implements $ne condition for Quantity
+func (f *OrderItemFilterBuilder) QuantityNe(value int64) *OrderItemFilterBuilder {
+	f.NotEqualTo(OrderItemFieldQuantity, value)
+
+	return f
+}
+
+// QuantityIn() implements $in condition for Quantity
+func (f *OrderItemFilterBuilder) QuantityIn(values ...int64) *OrderItemFilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In(OrderItemFieldQuantity, a)
+
+	return f
+}
+
+// QuantityGt() implements $gt condition for Quantity
+func (f *OrderItemFilterBuilder) QuantityGt(value int64) *OrderItemFilterBuilder {
+	f.Gt(OrderItemFieldQuantity, value)
+
+	return f
+}
+
+// QuantityGte() implements $gte condition for Quantity
+func (f *OrderItemFilterBuilder) QuantityGte(value int64) *OrderItemFilterBuilder {
+	f.Gte(OrderItemFieldQuantity, value)
+
+	return f
+}
+
+// QuantityLt() implements $lt condition for Quantity
+func (f *OrderItemFilterBuilder) QuantityLt(value int64) *OrderItemFilterBuilder {
+	f.Lt(OrderItemFieldQuantity, value)
+
+	return f
+}
+
+// QuantityLte() implements $lte condition for Quantity
+func (f *OrderItemFilterBuilder) QuantityLte(value int64) *OrderItemFilterBuilder {
+	f.Lte(OrderItemFieldQuantity, value)
+
+	return f
+}
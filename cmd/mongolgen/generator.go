@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// generatorImports are used by every generated file
+var generatorImports = map[string]string{
+	"context":                                   "context",
+	"go.mongodb.org/mongo-driver/bson":          "bson",
+	"go.mongodb.org/mongo-driver/mongo":         "mongo",
+	"go.mongodb.org/mongo-driver/mongo/options": "options",
+	mongolImportPath:                            "mongol",
+}
+
+// importSpec of the generated file
+type importSpec struct {
+	Name string
+	Path string
+}
+
+// generate() returns formatted source of the models
+func generate(pkg *Package) ([]byte, error) {
+	imports := make(map[string]string)
+
+	for path, name := range pkg.Imports {
+		imports[path] = name
+	}
+
+	for path, name := range generatorImports {
+		if existing, ok := imports[path]; ok && existing != name {
+			return nil, fmt.Errorf("package %s has to be imported as %s", path, name)
+		}
+
+		imports[path] = name
+	}
+
+	var std, other []importSpec
+
+	for path, name := range imports {
+		spec := importSpec{Name: name, Path: path}
+		if name == filepath.Base(path) {
+			spec.Name = ""
+		}
+
+		// standard packages have no dots in the first element of the path
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+
+	for _, specs := range [][]importSpec{std, other} {
+		sort.Slice(specs, func(i, j int) bool {
+			return specs[i].Path < specs[j].Path
+		})
+	}
+
+	var buf bytes.Buffer
+
+	err := fileTemplate.Execute(&buf, struct {
+		Package    *Package
+		StdImports []importSpec
+		Imports    []importSpec
+	}{pkg, std, other})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by mongolgen. DO NOT EDIT.
+
+package {{ .Package.Name }}
+
+import (
+{{- range .StdImports }}
+	{{ if .Name }}{{ .Name }} {{ end }}"{{ .Path }}"
+{{- end }}
+{{ range .Imports }}
+	{{ if .Name }}{{ .Name }} {{ end }}"{{ .Path }}"
+{{- end }}
+)
+
+{{ range .Package.Models }}{{ template "model" . }}{{ end }}
+`))
+
+var _ = template.Must(fileTemplate.New("model").Parse(`
+{{- $model := .Name -}}
+// {{ $model }}CollectionName is the collection of {{ $model }} documents
+const {{ $model }}CollectionName = "{{ .Collection }}"
+
+// BSON keys of {{ $model }} fields
+const (
+{{- range .Fields }}
+	{{ $model }}Field{{ .Name }} = "{{ .Key }}"
+{{- end }}
+)
+
+// {{ $model }}Indexes() returns indexes declared by mongol tags of {{ $model }} fields
+func {{ $model }}Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+{{- range .Indexes }}
+		{
+			Keys:    bson.D{ {Key: {{ $model }}Field{{ .Name }}, Value: {{ if .Index.Desc }}-1{{ else }}1{{ end }}} },
+			Options: options.Index(){{ if .Index.Unique }}.SetUnique(true){{ end }}{{ if .Index.Sparse }}.SetSparse(true){{ end }},
+		},
+{{- end }}
+	}
+}
+
+// {{ $model }}Collection() returns the definition of {{ $model }} collection for mongol.Registry
+func {{ $model }}Collection() mongol.CollectionDefinition {
+	return mongol.CollectionDefinition{
+		Name:    {{ $model }}CollectionName,
+		Model:   &{{ $model }}{},
+		Indexes: {{ $model }}Indexes(),
+	}
+}
+
+// {{ $model }}Repository is a typed repository of {{ $model }} documents
+type {{ $model }}Repository struct {
+	mongol.Storage
+}
+
+// New{{ $model }}Repository() is a constructor for {{ $model }}Repository struct
+func New{{ $model }}Repository(s mongol.Storage) *{{ $model }}Repository {
+	return &{{ $model }}Repository{Storage: s}
+}
+
+// GetByID() returns {{ $model }} by ID
+func (r *{{ $model }}Repository) GetByID(ctx context.Context, id string, opts ...*options.FindOneOptions) (*{{ $model }}, error) {
+	m := &{{ $model }}{}
+	if err := r.GetOneByID(ctx, id, m, opts...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// GetOne() returns {{ $model }} matching the filter
+func (r *{{ $model }}Repository) GetOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) (*{{ $model }}, error) {
+	m := &{{ $model }}{}
+	if err := r.GetOneByFilter(ctx, filter, m, opts...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Find() returns all {{ $model }} documents matching the filter
+func (r *{{ $model }}Repository) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) ([]*{{ $model }}, error) {
+	docs := []*{{ $model }}{}
+	if err := r.FindAllByFilter(ctx, filter, &docs, opts...); err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// Insert() inserts {{ $model }} and returns its ID
+func (r *{{ $model }}Repository) Insert(ctx context.Context, m *{{ $model }}, opts ...*options.InsertOneOptions) (string, error) {
+	return r.InsertOne(ctx, m, opts...)
+}
+
+// Update() updates {{ $model }} by its ID
+func (r *{{ $model }}Repository) Update(ctx context.Context, m *{{ $model }}, opts ...*options.UpdateOptions) error {
+	return r.UpdateOne(ctx, m, opts...)
+}
+
+// Delete() deletes {{ $model }} by ID
+func (r *{{ $model }}Repository) Delete(ctx context.Context, id string) error {
+	return r.DeleteOneByID(ctx, id)
+}
+
+// Count() returns the number of {{ $model }} documents matching the filter
+func (r *{{ $model }}Repository) Count(ctx context.Context, filter interface{}) (int64, error) {
+	return r.CountByFilter(ctx, filter)
+}
+
+// EnsureIndexes() creates indexes declared by mongol tags of {{ $model }} fields
+func (r *{{ $model }}Repository) EnsureIndexes(ctx context.Context) error {
+	for _, index := range {{ $model }}Indexes() {
+		if _, err := r.CreateIndex(ctx, index.Keys, index.Options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// {{ $model }}FilterBuilder builds filters of {{ $model }} documents with typed values
+type {{ $model }}FilterBuilder struct {
+	*mongol.FilterBuilder
+}
+
+// {{ $model }}Filter() initializes a new {{ $model }}FilterBuilder
+func {{ $model }}Filter() *{{ $model }}FilterBuilder {
+	return &{{ $model }}FilterBuilder{FilterBuilder: mongol.NewFilterBuilder()}
+}
+{{ range .Fields }}{{ if .Typed }}
+// {{ .Name }}Eq() implements $eq condition for {{ .Name }}
+func (f *{{ $model }}FilterBuilder) {{ .Name }}Eq(value {{ .Type }}) *{{ $model }}FilterBuilder {
+	f.EqualTo({{ $model }}Field{{ .Name }}, value)
+
+	return f
+}
+
+// {{ .Name }}Ne() implements $ne condition for {{ .Name }}
+func (f *{{ $model }}FilterBuilder) {{ .Name }}Ne(value {{ .Type }}) *{{ $model }}FilterBuilder {
+	f.NotEqualTo({{ $model }}Field{{ .Name }}, value)
+
+	return f
+}
+
+// {{ .Name }}In() implements $in condition for {{ .Name }}
+func (f *{{ $model }}FilterBuilder) {{ .Name }}In(values ...{{ .Type }}) *{{ $model }}FilterBuilder {
+	a := make(bson.A, len(values))
+	for i := range values {
+		a[i] = values[i]
+	}
+
+	f.In({{ $model }}Field{{ .Name }}, a)
+
+	return f
+}
+{{ if .Ordered }}
+// {{ .Name }}Gt() implements $gt condition for {{ .Name }}
+func (f *{{ $model }}FilterBuilder) {{ .Name }}Gt(value {{ .Type }}) *{{ $model }}FilterBuilder {
+	f.Gt({{ $model }}Field{{ .Name }}, value)
+
+	return f
+}
+
+// {{ .Name }}Gte() implements $gte condition for {{ .Name }}
+func (f *{{ $model }}FilterBuilder) {{ .Name }}Gte(value {{ .Type }}) *{{ $model }}FilterBuilder {
+	f.Gte({{ $model }}Field{{ .Name }}, value)
+
+	return f
+}
+
+// {{ .Name }}Lt() implements $lt condition for {{ .Name }}
+func (f *{{ $model }}FilterBuilder) {{ .Name }}Lt(value {{ .Type }}) *{{ $model }}FilterBuilder {
+	f.Lt({{ $model }}Field{{ .Name }}, value)
+
+	return f
+}
+
+// {{ .Name }}Lte() implements $lte condition for {{ .Name }}
+func (f *{{ $model }}FilterBuilder) {{ .Name }}Lte(value {{ .Type }}) *{{ $model }}FilterBuilder {
+	f.Lte({{ $model }}Field{{ .Name }}, value)
+
+	return f
+}
+{{ end }}{{ end }}
+{{- end }}
+`))
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("mongolgen", func() {
+	Describe("parsePackage()", func() {
+		It("should parse models of the file", func() {
+			pkg, err := parsePackage("example", "models.go", nil)
+			Expect(err).To(BeNil())
+			Expect(pkg.Name).To(Equal("example"))
+			Expect(pkg.Models).To(HaveLen(2))
+
+			user := pkg.Models[0]
+			Expect(user.Name).To(Equal("User"))
+			Expect(user.Collection).To(Equal("people"))
+
+			keys := make(map[string]string)
+			for _, f := range user.Fields {
+				keys[f.Name] = f.Key
+			}
+
+			Expect(keys).To(Equal(map[string]string{
+				"ID":        "_id",
+				"CreatedAt": "created_at",
+				"UpdatedAt": "updated_at",
+				"Email":     "email",
+				"Name":      "name",
+				"Age":       "age",
+				"Tags":      "tags",
+				"LastLogin": "last_login",
+				"Settings":  "settings",
+				"Address":   "address",
+			}))
+
+			Expect(user.Indexes()).To(HaveLen(2))
+			Expect(*user.Indexes()[1].Index).To(Equal(Index{Desc: true, Sparse: true}))
+		})
+
+		It("should return an error for unknown models", func() {
+			_, err := parsePackage("example", "", []string{"User", "Order"})
+			Expect(err).To(MatchError("model Order embedding mongol.BaseDocument was not found"))
+		})
+	})
+
+	Describe("generate()", func() {
+		It("should match the generated example", func() {
+			pkg, err := parsePackage("example", "", []string{"User", "OrderItem"})
+			Expect(err).To(BeNil())
+
+			src, err := generate(pkg)
+			Expect(err).To(BeNil())
+
+			expected, err := ioutil.ReadFile(filepath.Join("example", "models_mongol.go"))
+			Expect(err).To(BeNil())
+			Expect(string(src)).To(Equal(string(expected)))
+		})
+	})
+
+	Describe("run()", func() {
+		It("should write the output next to the file", func() {
+			dir, err := ioutil.TempDir("", "mongolgen")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+
+			src, err := ioutil.ReadFile(filepath.Join("example", "models.go"))
+			Expect(err).To(BeNil())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "models.go"), src, 0644)).To(Succeed())
+
+			Expect(run(dir, "models.go", "", "")).To(Succeed())
+			Expect(filepath.Join(dir, "models_mongol.go")).To(BeAnExistingFile())
+		})
+	})
+
+	Describe("collectionName()", func() {
+		It("should convert model names to snake_case plural", func() {
+			for name, collection := range map[string]string{
+				"User":       "users",
+				"OrderItem":  "order_items",
+				"HTTPLog":    "http_logs",
+				"Category":   "categories",
+				"Key":        "keys",
+				"Address":    "addresses",
+				"Box":        "boxes",
+				"BatchMatch": "batch_matches",
+			} {
+				Expect(collectionName(name)).To(Equal(collection), name)
+			}
+		})
+	})
+})
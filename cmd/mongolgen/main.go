@@ -0,0 +1,73 @@
+// mongolgen generates typed repositories, field constants, filter helpers and index declarations
+// for models embedding mongol.BaseDocument
+//
+// Usage:
+//
+//	//go:generate mongolgen -type User,Order
+//
+// Types are taken from the file of the go:generate directive if -type is empty,
+// the output is written to <file>_mongol.go next to it
+//
+// Fields are configured with mongol struct tags:
+//
+//	mongol.BaseDocument `bson:",inline" mongol:"collection=people"`
+//	Email string        `bson:"email" mongol:"unique"`
+//	Age   int           `bson:"age" mongol:"index,desc,sparse"`
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	var (
+		typeNames = flag.String("type", "", "comma-separated list of model types, all models of the file are used if empty")
+		output    = flag.String("output", "", "output file, <file>_mongol.go by default")
+		dir       = flag.String("dir", ".", "directory of the package")
+	)
+
+	flag.Parse()
+
+	if err := run(*dir, os.Getenv("GOFILE"), *typeNames, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "mongolgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run() generates the code of the models in the package directory
+func run(dir, file, typeNames, output string) error {
+	var types []string
+	if typeNames != "" {
+		types = strings.Split(typeNames, ",")
+	}
+
+	pkg, err := parsePackage(dir, file, types)
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(pkg)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		output = defaultOutput(file)
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, output), src, 0644)
+}
+
+// defaultOutput() returns <file>_mongol.go for the file of go:generate directive
+func defaultOutput(file string) string {
+	if file == "" {
+		return "models_mongol.go"
+	}
+
+	return strings.TrimSuffix(filepath.Base(file), ".go") + "_mongol.go"
+}
@@ -0,0 +1,471 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	mongolImportPath    = "github.com/wajox/mongol"
+	primitiveImportPath = "go.mongodb.org/mongo-driver/bson/primitive"
+	baseDocumentName    = "BaseDocument"
+	generatedFileSuffix = "_mongol.go"
+)
+
+var (
+	errNoModels = errors.New("no models embedding mongol.BaseDocument were found")
+)
+
+// Package of the models
+type Package struct {
+	Name    string
+	Models  []*Model
+	Imports map[string]string
+}
+
+// Model is a struct embedding mongol.BaseDocument
+type Model struct {
+	Name       string
+	Collection string
+	Fields     []*Field
+}
+
+// Field of the model
+type Field struct {
+	Name string
+	Key  string
+	Type string
+	// Typed fields get filter helpers, see isTypedExpr()
+	Typed   bool
+	Ordered bool
+	Index   *Index
+}
+
+// Index declared by mongol tag of the field
+type Index struct {
+	Unique bool
+	Sparse bool
+	Desc   bool
+}
+
+// Indexes() returns fields with indexes
+func (m *Model) Indexes() []*Field {
+	var fields []*Field
+
+	for _, f := range m.Fields {
+		if f.Index != nil {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// parsePackage() parses models of the package directory, only models of the file are used
+// if the file is set and types are empty
+func parsePackage(dir, file string, typeNames []string) (*Package, error) {
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return !strings.HasSuffix(name, "_test.go") && !strings.HasSuffix(name, generatedFileSuffix)
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var astPkg *ast.Package
+	for _, p := range pkgs {
+		astPkg = p
+	}
+
+	pkg := &Package{
+		Name:    astPkg.Name,
+		Imports: make(map[string]string),
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range typeNames {
+		wanted[strings.TrimSpace(name)] = true
+	}
+
+	fileNames := make([]string, 0, len(astPkg.Files))
+	for name := range astPkg.Files {
+		fileNames = append(fileNames, name)
+	}
+
+	sort.Strings(fileNames)
+
+	for _, name := range fileNames {
+		if len(wanted) == 0 && file != "" && filepath.Base(name) != filepath.Base(file) {
+			continue
+		}
+
+		models, err := parseFile(pkg, astPkg.Files[name], wanted)
+		if err != nil {
+			return nil, err
+		}
+
+		pkg.Models = append(pkg.Models, models...)
+	}
+
+	for name := range wanted {
+		if !pkg.hasModel(name) {
+			return nil, fmt.Errorf("model %s embedding mongol.BaseDocument was not found", name)
+		}
+	}
+
+	if len(pkg.Models) == 0 {
+		return nil, errNoModels
+	}
+
+	return pkg, nil
+}
+
+func (p *Package) hasModel(name string) bool {
+	for _, m := range p.Models {
+		if m.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseFile() returns models of the file
+func parseFile(pkg *Package, f *ast.File, wanted map[string]bool) ([]*Model, error) {
+	imports := fileImports(f)
+
+	mongolName, ok := importName(imports, mongolImportPath)
+	if !ok {
+		return nil, nil
+	}
+
+	var models []*Model
+
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || (len(wanted) > 0 && !wanted[ts.Name.Name]) {
+				continue
+			}
+
+			m, err := parseModel(pkg, ts.Name.Name, st, mongolName, imports)
+			if err != nil {
+				return nil, err
+			}
+
+			if m != nil {
+				models = append(models, m)
+			}
+		}
+	}
+
+	return models, nil
+}
+
+// parseModel() returns nil if the struct does not embed mongol.BaseDocument
+func parseModel(pkg *Package, name string, st *ast.StructType, mongolName string, imports map[string]string) (*Model, error) {
+	m := &Model{Name: name, Collection: collectionName(name)}
+	isModel := false
+
+	for _, field := range st.Fields.List {
+		tag := reflect.StructTag("")
+		if field.Tag != nil {
+			value, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			tag = reflect.StructTag(value)
+		}
+
+		key, inline, skip := bsonKey(tag)
+		opts := tagOptions(tag.Get("mongol"))
+
+		if len(field.Names) == 0 {
+			if !isBaseDocument(field.Type, mongolName) {
+				continue
+			}
+
+			isModel = true
+
+			if c, ok := opts["collection"]; ok && c != "" {
+				m.Collection = c
+			}
+
+			prefix := ""
+			if !inline {
+				prefix = strings.ToLower(baseDocumentName) + "."
+			}
+
+			m.Fields = append(m.Fields, baseDocumentFields(pkg, prefix)...)
+
+			continue
+		}
+
+		if skip {
+			continue
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+
+			fieldKey := key
+			if fieldKey == "" {
+				fieldKey = strings.ToLower(ident.Name)
+			}
+
+			typeName := types.ExprString(field.Type)
+			typed := isTypedExpr(field.Type)
+
+			// packages of the type are imported for filter helpers only
+			if typed {
+				if err := addTypeImports(pkg, field.Type, imports); err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", name, ident.Name, err)
+				}
+			}
+
+			m.Fields = append(m.Fields, &Field{
+				Name:    ident.Name,
+				Key:     fieldKey,
+				Type:    typeName,
+				Typed:   typed,
+				Ordered: isOrdered(typeName),
+				Index:   parseIndex(opts),
+			})
+		}
+	}
+
+	if !isModel {
+		return nil, nil
+	}
+
+	return m, nil
+}
+
+// isTypedExpr() returns false for anonymous structs, interfaces with methods, functions and channels,
+// such types can not be used in signatures of filter helpers
+func isTypedExpr(expr ast.Expr) bool {
+	typed := true
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch t := n.(type) {
+		case *ast.StructType, *ast.FuncType, *ast.ChanType:
+			typed = false
+		case *ast.InterfaceType:
+			typed = typed && len(t.Methods.List) == 0
+		}
+
+		return typed
+	})
+
+	return typed
+}
+
+// baseDocumentFields() returns fields of mongol.BaseDocument
+func baseDocumentFields(pkg *Package, prefix string) []*Field {
+	pkg.Imports["time"] = "time"
+	pkg.Imports[primitiveImportPath] = "primitive"
+
+	return []*Field{
+		{Name: "ID", Key: prefix + "_id", Type: "primitive.ObjectID", Typed: true},
+		{Name: "CreatedAt", Key: prefix + "created_at", Type: "time.Time", Typed: true, Ordered: true},
+		{Name: "UpdatedAt", Key: prefix + "updated_at", Type: "time.Time", Typed: true, Ordered: true},
+	}
+}
+
+// isBaseDocument() checks that the embedded field is mongol.BaseDocument or *mongol.BaseDocument
+func isBaseDocument(expr ast.Expr, mongolName string) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != baseDocumentName {
+		return false
+	}
+
+	x, ok := sel.X.(*ast.Ident)
+
+	return ok && x.Name == mongolName
+}
+
+// addTypeImports() registers packages used by the field type
+func addTypeImports(pkg *Package, expr ast.Expr, imports map[string]string) error {
+	var err error
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		x, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		path, ok := imports[x.Name]
+		if !ok {
+			err = fmt.Errorf("unknown package %s", x.Name)
+			return false
+		}
+
+		if name, exists := pkg.Imports[path]; exists && name != x.Name {
+			err = fmt.Errorf("package %s is imported as %s and %s", path, name, x.Name)
+			return false
+		}
+
+		pkg.Imports[path] = x.Name
+
+		return false
+	})
+
+	return err
+}
+
+// fileImports() maps names of the imported packages to their paths
+func fileImports(f *ast.File) map[string]string {
+	imports := make(map[string]string)
+
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := filepath.Base(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		imports[name] = path
+	}
+
+	return imports
+}
+
+func importName(imports map[string]string, path string) (string, bool) {
+	for name, p := range imports {
+		if p == path {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// bsonKey() parses bson tag like the driver does
+func bsonKey(tag reflect.StructTag) (key string, inline, skip bool) {
+	parts := strings.Split(tag.Get("bson"), ",")
+
+	for _, o := range parts[1:] {
+		if o == "inline" {
+			inline = true
+		}
+	}
+
+	return parts[0], inline, parts[0] == "-"
+}
+
+// tagOptions() parses mongol tag, e.g. "unique,sparse" or "collection=people"
+func tagOptions(tag string) map[string]string {
+	opts := make(map[string]string)
+
+	for _, o := range strings.Split(tag, ",") {
+		o = strings.TrimSpace(o)
+		if o == "" {
+			continue
+		}
+
+		kv := strings.SplitN(o, "=", 2)
+		if len(kv) == 2 {
+			opts[kv[0]] = kv[1]
+		} else {
+			opts[kv[0]] = ""
+		}
+	}
+
+	return opts
+}
+
+// parseIndex() returns nil if the field has no index options
+func parseIndex(opts map[string]string) *Index {
+	_, index := opts["index"]
+	_, unique := opts["unique"]
+
+	if !index && !unique {
+		return nil
+	}
+
+	_, sparse := opts["sparse"]
+	_, desc := opts["desc"]
+
+	return &Index{Unique: unique, Sparse: sparse, Desc: desc}
+}
+
+// isOrdered() returns true for types compared by $gt and $lt
+func isOrdered(typeName string) bool {
+	switch strings.TrimPrefix(typeName, "*") {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "time.Time", "time.Duration", "primitive.Decimal128":
+		return true
+	default:
+		return false
+	}
+}
+
+// collectionName() converts the model name to snake_case plural, e.g. OrderItem to order_items
+func collectionName(name string) string {
+	var b strings.Builder
+
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		b.WriteRune(r)
+	}
+
+	s := b.String()
+
+	switch {
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsAny(s[len(s)-2:len(s)-1], "aeiou"):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
@@ -2,13 +2,18 @@ package mongol
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 const (
 	// numeric code for MongoDB duplication error
 	DuplicationErrorCode = 11000
+	// numeric code for MongoDB document validation error
+	ValidationErrorCode = 121
 )
 
 var (
@@ -20,16 +25,279 @@ var (
 	ErrDocumentNotModified = errors.New("document wasn't modified")
 	// ErrInvalidObjectID appears then the ID has invalid format
 	ErrInvalidObjectID = errors.New("invalid objectID")
+	// ErrDocumentValidation appears then the document does not pass the collection validator
+	ErrDocumentValidation = errors.New("document failed validation")
+	// ErrClientClosed appears then the operation is started after the client was closed
+	ErrClientClosed = errors.New("client is closed")
+	// ErrCollectionNotRegistered appears then the collection was not registered in the Registry
+	ErrCollectionNotRegistered = errors.New("collection is not registered")
+	// ErrCollectionAlreadyRegistered appears then the collection name or model type is already registered
+	ErrCollectionAlreadyRegistered = errors.New("collection is already registered")
+	// ErrTenantMissing appears then the tenant-aware collection is used with a context without tenant
+	ErrTenantMissing = errors.New("tenant is missing in the context")
+	// ErrScopeNotRegistered appears then the named scope was not added to the collection
+	ErrScopeNotRegistered = errors.New("scope is not registered")
+	// ErrCircuitOpen appears then the operation is rejected by the open CircuitBreaker
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+	// ErrWriteConflict appears then a concurrent operation modified the document, see WriteConflictError
+	ErrWriteConflict = errors.New("write conflict")
+	// ErrTimeout appears then the operation exceeded its timeout, see TimeoutError
+	ErrTimeout = errors.New("operation timed out")
+	// ErrNetwork appears then the connection to the server failed, see NetworkError
+	ErrNetwork = errors.New("network error")
+	// ErrInvalidID appears then the ID can not be converted by the IDStrategy of the collection
+	ErrInvalidID = errors.New("invalid ID")
+	// ErrInvalidPatch appears then the patch can not be applied to the model, see PatchError
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrUnknownField appears then the field does not exist in the model, see FieldPathError
+	ErrUnknownField = errors.New("unknown field")
+	// ErrRelationNotRegistered appears then the populated relation was not added to the collection
+	ErrRelationNotRegistered = errors.New("relation is not registered")
+	// ErrStopIteration can be returned by the callback of Iterate() to stop the iteration without an error
+	ErrStopIteration = errors.New("stop iteration")
+	// ErrPatchTestFailed appears then a test operation of JSON Patch does not match the document
+	ErrPatchTestFailed = errors.New("patch test failed")
+	// ErrInvalidSequenceCount appears then Sequence.Reserve() is called with a count less than 1
+	ErrInvalidSequenceCount = errors.New("sequence count must be positive")
 )
 
+// HandleError() converts driver errors to typed errors:
+// *DuplicateKeyError, *ValidationFailedError, *WriteConflictError, *TimeoutError and *NetworkError
+// other errors are returned as is
+func HandleError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case mongo.WriteException:
+		for _, we := range e.WriteErrors {
+			if typed := typedWriteError(we); typed != nil {
+				return wrapTypedError(typed, err)
+			}
+		}
+	case mongo.BulkWriteException:
+		for _, we := range e.WriteErrors {
+			if typed := typedWriteError(we.WriteError); typed != nil {
+				return wrapTypedError(typed, err)
+			}
+		}
+	case mongo.CommandError:
+		switch e.Code {
+		case DuplicationErrorCode:
+			return newDuplicateKeyError(e.Message, e.Raw, err)
+		case ValidationErrorCode:
+			return &ValidationFailedError{Details: e.Raw, Err: err}
+		case WriteConflictErrorCode:
+			return &WriteConflictError{Err: err}
+		}
+	}
+
+	switch {
+	case mongo.IsTimeout(err):
+		return &TimeoutError{Err: err}
+	case mongo.IsNetworkError(err):
+		return &NetworkError{Err: err}
+	}
+
+	return err
+}
+
 // HandleDuplicationErr() checks exception type
 // if the error has occurred due to duplication problem then
 // the method returns ErrDocumentDuplication
+// Deprecated: use HandleError(), it returns *DuplicateKeyError with the index and the key
 func HandleDuplicationErr(err error) error {
-	mngErr, isWriteErr := err.(mongo.WriteException)
-	if !isWriteErr || mngErr.WriteErrors[0].Code != DuplicationErrorCode {
-		return err
+	var dupErr *DuplicateKeyError
+	if errors.As(HandleError(err), &dupErr) {
+		return ErrDocumentDuplication
+	}
+
+	return err
+}
+
+// HandleWriteError() converts a single write error
+// to *DuplicateKeyError, *ValidationFailedError or *WriteConflictError by its code
+func HandleWriteError(we mongo.WriteError) error {
+	if typed := typedWriteError(we); typed != nil {
+		return typed
+	}
+
+	return we
+}
+
+// typedWriteError() returns nil if the write error has no typed counterpart
+func typedWriteError(we mongo.WriteError) error {
+	switch we.Code {
+	case DuplicationErrorCode:
+		return newDuplicateKeyError(we.Message, we.Raw, we)
+	case ValidationErrorCode:
+		return &ValidationFailedError{Details: we.Details, Err: we}
+	case WriteConflictErrorCode:
+		return &WriteConflictError{Err: we}
+	default:
+		return nil
+	}
+}
+
+// wrapTypedError() replaces the single write error of the typed error by the whole exception
+func wrapTypedError(typed, err error) error {
+	switch e := typed.(type) {
+	case *DuplicateKeyError:
+		e.Err = err
+	case *ValidationFailedError:
+		e.Err = err
+	case *WriteConflictError:
+		e.Err = err
+	}
+
+	return typed
+}
+
+var (
+	duplicateKeyIndexRe = regexp.MustCompile(`index: (\S+) dup key`)
+)
+
+// DuplicateKeyError appears then a unique index rejects the document,
+// errors.Is(err, ErrDocumentDuplication) is true for it
+type DuplicateKeyError struct {
+	// Index is a name of the unique index
+	Index string
+	// Key is the duplicated value, e.g. {"email": "john@example.com"},
+	// it is empty if the server does not report it
+	Key bson.M
+	// Err is the original driver error
+	Err error
+}
+
+func newDuplicateKeyError(message string, raw bson.Raw, err error) *DuplicateKeyError {
+	dupErr := &DuplicateKeyError{Err: err}
+
+	if m := duplicateKeyIndexRe.FindStringSubmatch(message); len(m) == 2 {
+		dupErr.Index = m[1]
+	}
+
+	if keyValue, ok := raw.Lookup("keyValue").DocumentOK(); ok {
+		key := bson.M{}
+		if bson.Unmarshal(keyValue, &key) == nil {
+			dupErr.Key = key
+		}
+	}
+
+	return dupErr
+}
+
+// Error()
+func (e *DuplicateKeyError) Error() string {
+	msg := ErrDocumentDuplication.Error()
+
+	if e.Index != "" {
+		msg += ": index " + e.Index
+	}
+
+	if len(e.Key) > 0 {
+		msg += fmt.Sprintf(" key %v", e.Key)
 	}
 
-	return ErrDocumentDuplication
+	return msg
+}
+
+// Is() matches ErrDocumentDuplication
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrDocumentDuplication
+}
+
+// Unwrap() returns the original driver error
+func (e *DuplicateKeyError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationFailedError appears then the document does not pass the collection validator,
+// errors.Is(err, ErrDocumentValidation) is true for it
+type ValidationFailedError struct {
+	// Details of the failed validation reported by the server
+	Details bson.Raw
+	Err     error
+}
+
+// Error()
+func (e *ValidationFailedError) Error() string {
+	if len(e.Details) == 0 {
+		return ErrDocumentValidation.Error()
+	}
+
+	return ErrDocumentValidation.Error() + ": " + e.Details.String()
+}
+
+// Is() matches ErrDocumentValidation
+func (e *ValidationFailedError) Is(target error) bool {
+	return target == ErrDocumentValidation
+}
+
+// Unwrap() returns the original driver error
+func (e *ValidationFailedError) Unwrap() error {
+	return e.Err
+}
+
+// WriteConflictError appears then a concurrent operation or transaction modified the document,
+// errors.Is(err, ErrWriteConflict) is true for it
+type WriteConflictError struct {
+	Err error
+}
+
+// Error()
+func (e *WriteConflictError) Error() string {
+	return ErrWriteConflict.Error() + ": " + e.Err.Error()
+}
+
+// Is() matches ErrWriteConflict
+func (e *WriteConflictError) Is(target error) bool {
+	return target == ErrWriteConflict
+}
+
+// Unwrap() returns the original driver error
+func (e *WriteConflictError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError appears then the operation exceeded its timeout,
+// errors.Is(err, ErrTimeout) is true for it
+type TimeoutError struct {
+	Err error
+}
+
+// Error()
+func (e *TimeoutError) Error() string {
+	return ErrTimeout.Error() + ": " + e.Err.Error()
+}
+
+// Is() matches ErrTimeout
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// Unwrap() returns the original error
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// NetworkError appears then the connection to the server failed,
+// errors.Is(err, ErrNetwork) is true for it
+type NetworkError struct {
+	Err error
+}
+
+// Error()
+func (e *NetworkError) Error() string {
+	return ErrNetwork.Error() + ": " + e.Err.Error()
+}
+
+// Is() matches ErrNetwork
+func (e *NetworkError) Is(target error) bool {
+	return target == ErrNetwork
+}
+
+// Unwrap() returns the original driver error
+func (e *NetworkError) Unwrap() error {
+	return e.Err
 }
@@ -1,16 +1,32 @@
 package mongol_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/wajox/mongol"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 var _ = Describe("Errors", func() {
+	dupMessage := "E11000 duplicate key error collection: db.users index: email_1 dup key: { email: \"john@example.com\" }"
+
+	dupRaw := func() bson.Raw {
+		raw, err := bson.Marshal(bson.M{
+			"code":     mongol.DuplicationErrorCode,
+			"errmsg":   dupMessage,
+			"keyValue": bson.M{"email": "john@example.com"},
+		})
+		Expect(err).To(BeNil())
+
+		return raw
+	}
+
 	Describe("HandleDuplicationErr()", func() {
 		It("should return duplication error", func() {
 			mongoErr := mongo.WriteException{
@@ -31,4 +47,77 @@ var _ = Describe("Errors", func() {
 			Expect(mongol.HandleDuplicationErr(mongoErr)).To(Equal(mongoErr))
 		})
 	})
+
+	Describe("HandleWriteError()", func() {
+		It("should classify write errors by code", func() {
+			Expect(errors.Is(mongol.HandleWriteError(mongo.WriteError{Code: mongol.DuplicationErrorCode}), mongol.ErrDocumentDuplication)).To(BeTrue())
+			Expect(errors.Is(mongol.HandleWriteError(mongo.WriteError{Code: mongol.ValidationErrorCode}), mongol.ErrDocumentValidation)).To(BeTrue())
+			Expect(errors.Is(mongol.HandleWriteError(mongo.WriteError{Code: mongol.WriteConflictErrorCode}), mongol.ErrWriteConflict)).To(BeTrue())
+
+			we := mongo.WriteError{Code: 1, Message: "some error"}
+			Expect(mongol.HandleWriteError(we)).To(Equal(we))
+		})
+	})
+
+	Describe("HandleError()", func() {
+		It("should report the duplicated index and key", func() {
+			mongoErr := mongo.WriteException{
+				WriteErrors: mongo.WriteErrors{
+					{Code: 1, Message: "other error"},
+					{Code: mongol.DuplicationErrorCode, Message: dupMessage, Raw: dupRaw()},
+				},
+			}
+
+			err := mongol.HandleError(mongoErr)
+
+			var dupErr *mongol.DuplicateKeyError
+			Expect(errors.As(err, &dupErr)).To(BeTrue())
+			Expect(dupErr.Index).To(Equal("email_1"))
+			Expect(dupErr.Key).To(Equal(bson.M{"email": "john@example.com"}))
+			Expect(dupErr.Error()).To(ContainSubstring("email_1"))
+			Expect(errors.Is(err, mongol.ErrDocumentDuplication)).To(BeTrue())
+
+			var we mongo.WriteException
+			Expect(errors.As(err, &we)).To(BeTrue())
+		})
+
+		It("should handle bulk write and command errors", func() {
+			bulkErr := mongo.BulkWriteException{
+				WriteErrors: []mongo.BulkWriteError{
+					{WriteError: mongo.WriteError{Code: mongol.ValidationErrorCode}},
+				},
+			}
+
+			var validationErr *mongol.ValidationFailedError
+			Expect(errors.As(mongol.HandleError(bulkErr), &validationErr)).To(BeTrue())
+			Expect(errors.Is(validationErr, mongol.ErrDocumentValidation)).To(BeTrue())
+
+			cmdErr := mongo.CommandError{Code: mongol.DuplicationErrorCode, Message: dupMessage, Raw: dupRaw()}
+
+			var dupErr *mongol.DuplicateKeyError
+			Expect(errors.As(mongol.HandleError(cmdErr), &dupErr)).To(BeTrue())
+			Expect(dupErr.Index).To(Equal("email_1"))
+
+			var conflictErr *mongol.WriteConflictError
+			Expect(errors.As(mongol.HandleError(mongo.CommandError{Code: mongol.WriteConflictErrorCode}), &conflictErr)).To(BeTrue())
+		})
+
+		It("should classify timeouts and network errors", func() {
+			var timeoutErr *mongol.TimeoutError
+			err := mongol.HandleError(fmt.Errorf("count: %w", context.DeadlineExceeded))
+			Expect(errors.As(err, &timeoutErr)).To(BeTrue())
+			Expect(errors.Is(err, mongol.ErrTimeout)).To(BeTrue())
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+
+			var networkErr *mongol.NetworkError
+			err = mongol.HandleError(mongo.CommandError{Labels: []string{"NetworkError"}})
+			Expect(errors.As(err, &networkErr)).To(BeTrue())
+			Expect(errors.Is(err, mongol.ErrNetwork)).To(BeTrue())
+		})
+
+		It("should return other errors as is", func() {
+			Expect(mongol.HandleError(nil)).To(BeNil())
+			Expect(mongol.HandleError(mongo.ErrNoDocuments)).To(Equal(mongo.ErrNoDocuments))
+		})
+	})
 })
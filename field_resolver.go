@@ -0,0 +1,277 @@
+package mongol
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FieldPathError appears then the Go field path does not exist in the model,
+// errors.Is(err, ErrUnknownField) is true for it
+type FieldPathError struct {
+	Model string
+	Path  string
+}
+
+// Error()
+func (e *FieldPathError) Error() string {
+	return ErrUnknownField.Error() + ": " + e.Model + "." + e.Path
+}
+
+// Is() matches ErrUnknownField
+func (e *FieldPathError) Is(target error) bool {
+	return target == ErrUnknownField
+}
+
+// FieldResolver maps Go field paths of the model, e.g. "Address.City", to BSON keys, e.g. "addr.city"
+// Fields of embedded structs with `bson:",inline"` tag like BaseDocument are resolved without prefix,
+// array indexes and positional operators are kept as is, e.g. "Items.$.Price" to "items.$.price"
+type FieldResolver struct {
+	model reflect.Type
+	cache sync.Map
+}
+
+// NewFieldResolver() is a constructor for FieldResolver of the model, e.g. &User{}
+func NewFieldResolver(model interface{}) *FieldResolver {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return &FieldResolver{model: t}
+}
+
+// Resolve() returns BSON key of the Go field path
+func (r *FieldResolver) Resolve(path string) (string, error) {
+	if key, ok := r.cache.Load(path); ok {
+		return key.(string), nil
+	}
+
+	key, ok := resolveFieldPath(r.model, path)
+	if !ok {
+		return "", &FieldPathError{Model: r.modelName(), Path: path}
+	}
+
+	r.cache.Store(path, key)
+
+	return key, nil
+}
+
+// MustResolve() is like Resolve() but panics if the path does not exist,
+// use it to declare field keys at package level, so typos fail at startup
+func (r *FieldResolver) MustResolve(path string) string {
+	key, err := r.Resolve(path)
+	if err != nil {
+		panic(err)
+	}
+
+	return key
+}
+
+// Validate() checks that all paths exist in the model
+func (r *FieldResolver) Validate(paths ...string) error {
+	for _, path := range paths {
+		if _, err := r.Resolve(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveKey() resolves the path if the resolver is set, unresolved paths are returned as is
+func resolveKey(r *FieldResolver, path string) (string, error) {
+	if r == nil {
+		return path, nil
+	}
+
+	key, err := r.Resolve(path)
+	if err != nil {
+		return path, err
+	}
+
+	return key, nil
+}
+
+func (r *FieldResolver) modelName() string {
+	if r.model == nil {
+		return "<nil>"
+	}
+
+	return r.model.Name()
+}
+
+// resolveFieldPath() walks the dotted Go field path through the model type
+func resolveFieldPath(t reflect.Type, path string) (string, bool) {
+	if t == nil || path == "" {
+		return "", false
+	}
+
+	segments := strings.Split(path, ".")
+	keys := make([]string, 0, len(segments))
+
+	for i := 0; i < len(segments); i++ {
+		seg := segments[i]
+
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		switch t.Kind() {
+		case reflect.Struct:
+			key, ft, ok := structFieldKey(t, seg)
+			if !ok {
+				return "", false
+			}
+
+			// inline structs selected by name do not add a key
+			if key != "" {
+				keys = append(keys, key)
+			}
+
+			t = ft
+		case reflect.Slice, reflect.Array:
+			t = t.Elem()
+
+			// fields of array elements are queried without index, e.g. "items.price"
+			if !isArrayPathSegment(seg) {
+				i--
+				continue
+			}
+
+			keys = append(keys, seg)
+		case reflect.Map:
+			if t.Key().Kind() != reflect.String || seg == "" {
+				return "", false
+			}
+
+			keys = append(keys, seg)
+			t = t.Elem()
+		case reflect.Interface:
+			// values of interface fields are not known, the rest of the path is kept as is
+			keys = append(keys, seg)
+		default:
+			return "", false
+		}
+	}
+
+	if len(keys) == 0 {
+		return "", false
+	}
+
+	return strings.Join(keys, "."), true
+}
+
+// structFieldKey() returns BSON key and type of the struct field by Go name,
+// fields of embedded structs are promoted like Go does, ambiguous names are not found
+func structFieldKey(t reflect.Type, name string) (string, reflect.Type, bool) {
+	key, ft, _, found := promotedFieldKey(t, name)
+
+	return key, ft, found == 1
+}
+
+// promotedFieldKey() returns the field of the least depth and the number of fields found at this depth
+func promotedFieldKey(t reflect.Type, name string) (key string, ft reflect.Type, depth, found int) {
+	var embedded []reflect.StructField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		fieldKey, inline, skip := bsonFieldKey(f)
+		if skip {
+			continue
+		}
+
+		if f.Name == name {
+			if inline {
+				fieldKey = ""
+			}
+
+			return fieldKey, f.Type, 0, 1
+		}
+
+		if f.Anonymous {
+			embedded = append(embedded, f)
+		}
+	}
+
+	for _, f := range embedded {
+		et := f.Type
+		for et.Kind() == reflect.Ptr {
+			et = et.Elem()
+		}
+
+		if et.Kind() != reflect.Struct {
+			continue
+		}
+
+		nestedKey, nestedType, nestedDepth, nestedFound := promotedFieldKey(et, name)
+		if nestedFound == 0 {
+			continue
+		}
+
+		nestedDepth++
+
+		switch {
+		case found > 0 && nestedDepth > depth:
+			continue
+		case found > 0 && nestedDepth == depth:
+			found += nestedFound
+			continue
+		}
+
+		embeddedKey, inline, _ := bsonFieldKey(f)
+
+		switch {
+		case inline:
+		case nestedKey == "":
+			nestedKey = embeddedKey
+		default:
+			nestedKey = embeddedKey + "." + nestedKey
+		}
+
+		key, ft, depth, found = nestedKey, nestedType, nestedDepth, nestedFound
+	}
+
+	return key, ft, depth, found
+}
+
+// bsonFieldKey() parses bson tag of the struct field like the driver does,
+// the key is the lowercased field name if the tag has no name
+func bsonFieldKey(f reflect.StructField) (key string, inline, skip bool) {
+	tag := strings.Split(f.Tag.Get("bson"), ",")
+
+	if tag[0] == "-" || f.PkgPath != "" {
+		return "", false, true
+	}
+
+	key = tag[0]
+	if key == "" {
+		key = strings.ToLower(f.Name)
+	}
+
+	return key, hasTagOption(tag, "inline"), false
+}
+
+// hasTagOption() checks options of the parsed struct tag
+func hasTagOption(tag []string, option string) bool {
+	for _, o := range tag[1:] {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isArrayPathSegment() returns true for array indexes and positional operators
+func isArrayPathSegment(seg string) bool {
+	if seg == "$" || seg == "$[]" || (strings.HasPrefix(seg, "$[") && strings.HasSuffix(seg, "]")) {
+		return true
+	}
+
+	i, err := strconv.Atoi(seg)
+
+	return err == nil && i >= 0
+}
@@ -0,0 +1,135 @@
+package mongol_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/wajox/mongol"
+)
+
+type ResolvedItem struct {
+	Name  string
+	Price int `bson:"price,omitempty"`
+}
+
+type ResolvedAudit struct {
+	CreatedBy string `bson:"created_by"`
+}
+
+type ResolvedOwner struct {
+	CreatedBy string `bson:"owner"`
+}
+
+type ResolvedHistory struct {
+	ResolvedOwner
+}
+
+// AmbiguousModel promotes CreatedBy of two embedded structs at the same depth
+type AmbiguousModel struct {
+	ResolvedAudit
+	ResolvedOwner
+}
+
+// ShadowedModel promotes CreatedBy of the least depth
+type ShadowedModel struct {
+	ResolvedHistory
+	ResolvedAudit
+}
+
+type ResolvedModel struct {
+	BaseDocument `bson:",inline"`
+	ResolvedAudit
+
+	Title   string                 `bson:"title,omitempty"`
+	Address *PatchAddress          `bson:"addr,omitempty"`
+	Items   []ResolvedItem         `bson:"items"`
+	Labels  map[string]string      `bson:"labels"`
+	Extra   map[string]interface{} `bson:"extra"`
+	Ignored string                 `bson:"-"`
+	secret  string
+}
+
+var _ = Describe("FieldResolver", func() {
+	var (
+		resolver *FieldResolver
+	)
+
+	BeforeEach(func() {
+		resolver = NewFieldResolver(&ResolvedModel{})
+	})
+
+	Describe(".Resolve()", func() {
+		It("should resolve Go field paths to BSON keys", func() {
+			for path, key := range map[string]string{
+				"ID":                      "_id",
+				"CreatedAt":               "created_at",
+				"BaseDocument.UpdatedAt":  "updated_at",
+				"CreatedBy":               "resolvedaudit.created_by",
+				"ResolvedAudit.CreatedBy": "resolvedaudit.created_by",
+				"Title":                   "title",
+				"Address.City":            "addr.city",
+				"Items":                   "items",
+				"Items.Name":              "items.name",
+				"Items.0.Price":           "items.0.price",
+				"Items.$.Price":           "items.$.price",
+				"Items.$[item].Price":     "items.$[item].price",
+				"Labels.env":              "labels.env",
+				"Extra.some.nested":       "extra.some.nested",
+			} {
+				resolved, err := resolver.Resolve(path)
+				Expect(err).To(BeNil(), path)
+				Expect(resolved).To(Equal(key), path)
+			}
+		})
+
+		It("should return FieldPathError for unknown fields", func() {
+			for _, path := range []string{
+				"",
+				"title",
+				"Ignored",
+				"secret",
+				"BaseDocument",
+				"Address.Cty",
+				"Title.Length",
+				"Items.Name.First",
+			} {
+				_, err := resolver.Resolve(path)
+				Expect(errors.Is(err, ErrUnknownField)).To(BeTrue(), path)
+
+				var fErr *FieldPathError
+				Expect(errors.As(err, &fErr)).To(BeTrue())
+				Expect(fErr.Model).To(Equal("ResolvedModel"))
+				Expect(fErr.Path).To(Equal(path))
+			}
+		})
+	})
+
+	Describe(".Resolve() of promoted fields", func() {
+		It("should not resolve ambiguous names", func() {
+			_, err := NewFieldResolver(&AmbiguousModel{}).Resolve("CreatedBy")
+			Expect(errors.Is(err, ErrUnknownField)).To(BeTrue())
+
+			Expect(NewFieldResolver(&AmbiguousModel{}).Resolve("ResolvedOwner.CreatedBy")).To(Equal("resolvedowner.owner"))
+		})
+
+		It("should resolve names of the least depth", func() {
+			Expect(NewFieldResolver(&ShadowedModel{}).Resolve("CreatedBy")).To(Equal("resolvedaudit.created_by"))
+		})
+	})
+
+	Describe(".MustResolve()", func() {
+		It("should panic for unknown fields", func() {
+			Expect(resolver.MustResolve("Address.City")).To(Equal("addr.city"))
+			Expect(func() { resolver.MustResolve("Address.Cty") }).To(Panic())
+		})
+	})
+
+	Describe(".Validate()", func() {
+		It("should check all paths", func() {
+			Expect(resolver.Validate("Title", "Address.City")).To(Succeed())
+			Expect(errors.Is(resolver.Validate("Title", "Titel"), ErrUnknownField)).To(BeTrue())
+		})
+	})
+})
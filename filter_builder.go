@@ -6,7 +6,9 @@ import (
 
 // FilterBuilder
 type FilterBuilder struct {
-	query bson.M
+	query    bson.M
+	resolver *FieldResolver
+	err      error
 }
 
 // NewFilterBuilder() initializes a new FilterBuilder
@@ -16,14 +18,46 @@ func NewFilterBuilder() *FilterBuilder {
 	}
 }
 
-// GetQuery() returns compiled query for *FilterBuilder
+// GetQuery() returns compiled query for *FilterBuilder, unresolved field paths are kept as is, see Build()
 func (fb *FilterBuilder) GetQuery() bson.M {
 	return fb.query
 }
 
+// Build() returns compiled query or the first error of field paths resolution
+func (fb *FilterBuilder) Build() (bson.M, error) {
+	if fb.err != nil {
+		return nil, fb.err
+	}
+
+	return fb.query, nil
+}
+
+// WithResolver() makes the builder accept Go field paths of the model, e.g. "Address.City",
+// unknown paths are reported by Err()
+func (fb *FilterBuilder) WithResolver(r *FieldResolver) *FilterBuilder {
+	fb.resolver = r
+
+	return fb
+}
+
+// Err() returns the first error of field paths resolution
+func (fb *FilterBuilder) Err() error {
+	return fb.err
+}
+
+// key() resolves the field path if the builder has a FieldResolver
+func (fb *FilterBuilder) key(path string) string {
+	key, err := resolveKey(fb.resolver, path)
+	if err != nil && fb.err == nil {
+		fb.err = err
+	}
+
+	return key
+}
+
 // Where() allows to set custom conditions for the query
 func (fb *FilterBuilder) Where(key string, query bson.M) *FilterBuilder {
-	fb.query[key] = query
+	fb.query[fb.key(key)] = query
 
 	return fb
 }
@@ -56,70 +90,85 @@ func (fb *FilterBuilder) And(query ...interface{}) *FilterBuilder {
 
 // EqualTo() implements $eq condition for the query
 func (fb *FilterBuilder) EqualTo(key string, value interface{}) *FilterBuilder {
-	fb.query[key] = bson.M{"$eq": value}
+	fb.query[fb.key(key)] = bson.M{"$eq": value}
 
 	return fb
 }
 
 // NotEqualTo() implements $ne condition for the query
 func (fb *FilterBuilder) NotEqualTo(key string, value interface{}) *FilterBuilder {
-	fb.query[key] = bson.M{"$ne": value}
+	fb.query[fb.key(key)] = bson.M{"$ne": value}
 
 	return fb
 }
 
 // In() implements $in condition for the query
 func (fb *FilterBuilder) In(key string, values bson.A) *FilterBuilder {
-	fb.query[key] = bson.M{"$in": values}
+	fb.query[fb.key(key)] = bson.M{"$in": values}
 
 	return fb
 }
 
 // NotIn() implements $nin condition for the query
 func (fb *FilterBuilder) NotIn(key string, values bson.A) *FilterBuilder {
-	fb.query[key] = bson.M{"$nin": values}
+	fb.query[fb.key(key)] = bson.M{"$nin": values}
 
 	return fb
 }
 
 // HasField() implements $exists:true condition for the query
 func (fb *FilterBuilder) HasField(key string) *FilterBuilder {
-	fb.query[key] = bson.M{"$exists": true}
+	fb.query[fb.key(key)] = bson.M{"$exists": true}
 
 	return fb
 }
 
 // HasNotField() implements $exists:false condition for the query
 func (fb *FilterBuilder) HasNotField(key string) *FilterBuilder {
-	fb.query[key] = bson.M{"$exists": false}
+	fb.query[fb.key(key)] = bson.M{"$exists": false}
 
 	return fb
 }
 
 // Gte() implements $gte condition for the query
 func (fb *FilterBuilder) Gte(key string, value interface{}) *FilterBuilder {
-	fb.query[key] = bson.M{"$gte": value}
+	fb.query[fb.key(key)] = bson.M{"$gte": value}
 
 	return fb
 }
 
 // Lte() implements $lte condition for the query
 func (fb *FilterBuilder) Lte(key string, value interface{}) *FilterBuilder {
-	fb.query[key] = bson.M{"$lte": value}
+	fb.query[fb.key(key)] = bson.M{"$lte": value}
 
 	return fb
 }
 
 // Gt() implements $gt condition for the query
 func (fb *FilterBuilder) Gt(key string, value interface{}) *FilterBuilder {
-	fb.query[key] = bson.M{"$gt": value}
+	fb.query[fb.key(key)] = bson.M{"$gt": value}
 
 	return fb
 }
 
 // Lt() implements $lt condition for the query
 func (fb *FilterBuilder) Lt(key string, value interface{}) *FilterBuilder {
-	fb.query[key] = bson.M{"$lt": value}
+	fb.query[fb.key(key)] = bson.M{"$lt": value}
 
 	return fb
 }
+
+// buildFilter() returns the query of *FilterBuilder, other filters are returned as is
+func buildFilter(filter interface{}) (interface{}, error) {
+	fb, ok := filter.(*FilterBuilder)
+	if !ok {
+		return filter, nil
+	}
+
+	query, err := fb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return query, nil
+}
@@ -1,6 +1,8 @@
 package mongol_test
 
 import (
+	"errors"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"go.mongodb.org/mongo-driver/bson"
@@ -161,5 +163,36 @@ var _ = Describe("FilterBuilder", func() {
 				})
 			})
 		})
+
+		Context("with field resolver", func() {
+			BeforeEach(func() {
+				filter = mongol.NewFilterBuilder().WithResolver(mongol.NewFieldResolver(&ResolvedModel{}))
+			})
+
+			It("should resolve Go field paths", func() {
+				filter.EqualTo("Address.City", "Berlin").Gte("CreatedAt", 10)
+
+				Expect(filter.Err()).To(BeNil())
+
+				query, err := filter.Build()
+				Expect(err).To(BeNil())
+				Expect(query).To(Equal(filter.GetQuery()))
+				Expect(filter.GetQuery()).To(Equal(bson.M{
+					"addr.city":  bson.M{"$eq": "Berlin"},
+					"created_at": bson.M{"$gte": 10},
+				}))
+			})
+
+			It("should report unknown fields", func() {
+				filter.EqualTo("Address.Cty", "Berlin").EqualTo("Title", "title")
+
+				Expect(errors.Is(filter.Err(), mongol.ErrUnknownField)).To(BeTrue())
+				Expect(filter.Err().Error()).To(Equal("unknown field: ResolvedModel.Address.Cty"))
+
+				query, err := filter.Build()
+				Expect(query).To(BeNil())
+				Expect(err).To(Equal(filter.Err()))
+			})
+		})
 	})
 })
@@ -0,0 +1,263 @@
+package mongol
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultCompositeIDSeparator separates parts of composite IDs in their string form
+	DefaultCompositeIDSeparator = ":"
+)
+
+var (
+	_ IDStrategy = ObjectIDStrategy{}
+	_ IDStrategy = UUIDStrategy{}
+	_ IDStrategy = StringIDStrategy{}
+	_ IDStrategy = Int64IDStrategy{}
+	_ IDStrategy = CompositeIDStrategy{}
+)
+
+// IDStrategy converts document IDs between the string form used by
+// GetOneByID(), ReplaceOneByID(), DeleteOneByID() and the value stored in the _id field
+type IDStrategy interface {
+	// Parse() converts the string form of an ID to the _id value
+	Parse(id string) (interface{}, error)
+	// Format() converts the _id value to its string form
+	Format(id interface{}) (string, error)
+	// Generate() returns a new _id value for a document without one
+	// nil means that the value is assigned by the driver or has to be set by the caller
+	Generate(ctx context.Context) (interface{}, error)
+}
+
+// ObjectIDStrategy is the default strategy for documents keyed by primitive.ObjectID
+type ObjectIDStrategy struct{}
+
+// Parse() converts hex-string to ObjectID
+func (ObjectIDStrategy) Parse(id string) (interface{}, error) {
+	return StringToObjectID(id)
+}
+
+// Format() converts ObjectID to hex-string
+func (ObjectIDStrategy) Format(id interface{}) (string, error) {
+	oid, ok := id.(primitive.ObjectID)
+	if !ok {
+		return "", ErrInvalidObjectID
+	}
+
+	return oid.Hex(), nil
+}
+
+// Generate() returns nil because ObjectIDs are assigned by the driver
+func (ObjectIDStrategy) Generate(context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+// UUIDStrategy is a strategy for documents keyed by UUID strings
+type UUIDStrategy struct{}
+
+// Parse() validates and normalizes UUID string
+func (UUIDStrategy) Parse(id string) (interface{}, error) {
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	return u.String(), nil
+}
+
+// Format() converts stored UUID to string
+func (UUIDStrategy) Format(id interface{}) (string, error) {
+	switch v := id.(type) {
+	case string:
+		return v, nil
+	case uuid.UUID:
+		return v.String(), nil
+	default:
+		return "", ErrInvalidID
+	}
+}
+
+// Generate() returns a new random UUID
+func (UUIDStrategy) Generate(context.Context) (interface{}, error) {
+	return uuid.New().String(), nil
+}
+
+// StringIDStrategy is a strategy for documents keyed by arbitrary strings
+type StringIDStrategy struct{}
+
+// Parse() returns the string as is
+func (StringIDStrategy) Parse(id string) (interface{}, error) {
+	if id == "" {
+		return nil, ErrInvalidID
+	}
+
+	return id, nil
+}
+
+// Format() returns the stored string
+func (StringIDStrategy) Format(id interface{}) (string, error) {
+	s, ok := id.(string)
+	if !ok {
+		return "", ErrInvalidID
+	}
+
+	return s, nil
+}
+
+// Generate() returns nil because string IDs have to be set by the caller
+func (StringIDStrategy) Generate(context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+// Int64IDStrategy is a strategy for documents keyed by int64 numbers
+// IDs are allocated from Sequence if it is set
+type Int64IDStrategy struct {
+	Sequence *Sequence
+}
+
+// Parse() converts decimal string to int64
+func (Int64IDStrategy) Parse(id string) (interface{}, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	return n, nil
+}
+
+// Format() converts stored number to decimal string
+func (Int64IDStrategy) Format(id interface{}) (string, error) {
+	switch v := id.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int:
+		return strconv.Itoa(v), nil
+	default:
+		return "", ErrInvalidID
+	}
+}
+
+// Generate() returns the next value of the Sequence
+// or nil if numeric IDs have to be set by the caller
+func (st Int64IDStrategy) Generate(ctx context.Context) (interface{}, error) {
+	if st.Sequence == nil {
+		return nil, nil
+	}
+
+	id, err := st.Sequence.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// CompositeIDStrategy is a strategy for documents keyed by embedded documents,
+// e.g. {_id: {tenant: "acme", number: 42}} with the string form "acme:42"
+type CompositeIDStrategy struct {
+	Keys []string
+	// Parts parse parts of the key by index, e.g. Int64IDStrategy{} for the number,
+	// parts without a strategy are parsed as strings
+	Parts     []IDStrategy
+	Separator string
+}
+
+// NewCompositeIDStrategy() is a constructor for CompositeIDStrategy struct
+func NewCompositeIDStrategy(keys ...string) CompositeIDStrategy {
+	return CompositeIDStrategy{Keys: keys, Separator: DefaultCompositeIDSeparator}
+}
+
+// WithParts() returns a copy of the strategy parsing parts of the key by given strategies
+func (st CompositeIDStrategy) WithParts(parts ...IDStrategy) CompositeIDStrategy {
+	st.Parts = parts
+
+	return st
+}
+
+func (st CompositeIDStrategy) separator() string {
+	if st.Separator == "" {
+		return DefaultCompositeIDSeparator
+	}
+
+	return st.Separator
+}
+
+// Parse() splits the string into parts of the composite key
+func (st CompositeIDStrategy) Parse(id string) (interface{}, error) {
+	parts := strings.Split(id, st.separator())
+	if len(parts) != len(st.Keys) {
+		return nil, ErrInvalidID
+	}
+
+	key := bson.D{}
+	for i := range st.Keys {
+		v, err := st.parsePart(i, parts[i])
+		if err != nil {
+			return nil, err
+		}
+
+		key = append(key, primitive.E{Key: st.Keys[i], Value: v})
+	}
+
+	return key, nil
+}
+
+// Format() joins parts of the composite key
+func (st CompositeIDStrategy) Format(id interface{}) (string, error) {
+	b, err := bson.Marshal(id)
+	if err != nil {
+		return "", ErrInvalidID
+	}
+
+	raw := bson.Raw(b)
+	parts := make([]string, len(st.Keys))
+
+	for i := range st.Keys {
+		v, err := raw.LookupErr(st.Keys[i])
+		if err != nil {
+			return "", ErrInvalidID
+		}
+
+		parts[i] = formatRawIDPart(v)
+	}
+
+	return strings.Join(parts, st.separator()), nil
+}
+
+// Generate() returns nil because composite IDs have to be set by the caller
+func (CompositeIDStrategy) Generate(context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func (st CompositeIDStrategy) parsePart(i int, part string) (interface{}, error) {
+	if i >= len(st.Parts) || st.Parts[i] == nil {
+		return part, nil
+	}
+
+	return st.Parts[i].Parse(part)
+}
+
+func formatRawIDPart(v bson.RawValue) string {
+	switch v.Type {
+	case bsontype.String:
+		return v.StringValue()
+	case bsontype.Int32:
+		return strconv.FormatInt(int64(v.Int32()), 10)
+	case bsontype.Int64:
+		return strconv.FormatInt(v.Int64(), 10)
+	case bsontype.ObjectID:
+		return v.ObjectID().Hex()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,138 @@
+package mongol_test
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/wajox/mongol"
+)
+
+var _ = Describe("IDStrategy", func() {
+	Describe("ObjectIDStrategy", func() {
+		st := mongol.ObjectIDStrategy{}
+
+		It("should parse and format ObjectID", func() {
+			oid := primitive.NewObjectID()
+
+			key, err := st.Parse(oid.Hex())
+			Expect(err).To(BeNil())
+			Expect(key).To(Equal(oid))
+
+			id, err := st.Format(key)
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal(oid.Hex()))
+		})
+
+		It("should not parse invalid ObjectID", func() {
+			_, err := st.Parse("123")
+			Expect(err).To(Equal(mongol.ErrInvalidObjectID))
+
+			_, err = st.Format("123")
+			Expect(err).To(Equal(mongol.ErrInvalidObjectID))
+		})
+	})
+
+	Describe("UUIDStrategy", func() {
+		st := mongol.UUIDStrategy{}
+
+		It("should generate, parse and format UUID", func() {
+			key, err := st.Generate(context.TODO())
+			Expect(err).To(BeNil())
+
+			parsed, err := st.Parse(key.(string))
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(key))
+
+			u := uuid.New()
+			id, err := st.Format(u)
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal(u.String()))
+		})
+
+		It("should not parse invalid UUID", func() {
+			_, err := st.Parse("123")
+			Expect(err).To(Equal(mongol.ErrInvalidID))
+		})
+	})
+
+	Describe("StringIDStrategy", func() {
+		st := mongol.StringIDStrategy{}
+
+		It("should parse and format string", func() {
+			key, err := st.Parse("order-1")
+			Expect(err).To(BeNil())
+			Expect(key).To(Equal("order-1"))
+
+			_, err = st.Parse("")
+			Expect(err).To(Equal(mongol.ErrInvalidID))
+
+			_, err = st.Format(1)
+			Expect(err).To(Equal(mongol.ErrInvalidID))
+		})
+	})
+
+	Describe("Int64IDStrategy", func() {
+		st := mongol.Int64IDStrategy{}
+
+		It("should parse and format int64", func() {
+			key, err := st.Parse("42")
+			Expect(err).To(BeNil())
+			Expect(key).To(Equal(int64(42)))
+
+			id, err := st.Format(int32(42))
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal("42"))
+
+			_, err = st.Parse("abc")
+			Expect(err).To(Equal(mongol.ErrInvalidID))
+		})
+	})
+
+	Describe("CompositeIDStrategy", func() {
+		st := mongol.NewCompositeIDStrategy("tenant", "number")
+
+		It("should parse and format composite key", func() {
+			key, err := st.Parse("acme:42")
+			Expect(err).To(BeNil())
+			Expect(key).To(Equal(bson.D{{Key: "tenant", Value: "acme"}, {Key: "number", Value: "42"}}))
+
+			id, err := st.Format(bson.M{"number": 42, "tenant": "acme"})
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal("acme:42"))
+		})
+
+		It("should parse parts by their strategies", func() {
+			oid := primitive.NewObjectID()
+			st := mongol.NewCompositeIDStrategy("tenant", "number", "owner").
+				WithParts(nil, mongol.Int64IDStrategy{}, mongol.ObjectIDStrategy{})
+
+			key, err := st.Parse("acme:42:" + oid.Hex())
+			Expect(err).To(BeNil())
+			Expect(key).To(Equal(bson.D{
+				{Key: "tenant", Value: "acme"},
+				{Key: "number", Value: int64(42)},
+				{Key: "owner", Value: oid},
+			}))
+
+			id, err := st.Format(key)
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal("acme:42:" + oid.Hex()))
+
+			_, err = st.Parse("acme:forty-two:" + oid.Hex())
+			Expect(err).To(Equal(mongol.ErrInvalidID))
+		})
+
+		It("should not parse invalid composite key", func() {
+			_, err := st.Parse("acme")
+			Expect(err).To(Equal(mongol.ErrInvalidID))
+
+			_, err = st.Format(bson.M{"tenant": "acme"})
+			Expect(err).To(Equal(mongol.ErrInvalidID))
+		})
+	})
+})
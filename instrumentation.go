@@ -0,0 +1,198 @@
+package mongol
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DBSystem is a value of db.system attribute of spans
+	DBSystem = "mongodb"
+
+	// error classes returned by ErrorClass()
+	ErrorClassNotFound    = "not_found"
+	ErrorClassDuplication = "duplication"
+	ErrorClassValidation  = "validation"
+	ErrorClassTimeout     = "timeout"
+	ErrorClassNetwork     = "network"
+	ErrorClassOther       = "error"
+
+	// SanitizedValue replaces values of sanitized filters
+	SanitizedValue = "?"
+)
+
+// Operation describes a Storage call observed by Instrumentation
+type Operation struct {
+	DBName     string
+	Collection string
+	// Method is one of *Method constants, e.g. GetOneByIDMethod
+	Method string
+	// Filter of the call, use SanitizedFilter() to export it
+	Filter interface{}
+	// Document is an inserted or replacing document, an update or a bulk of operations
+	Document interface{}
+	// Options of the call
+	Options interface{}
+
+	StartedAt time.Time
+	Duration  time.Duration
+	// Documents is a number of returned documents
+	Documents int64
+	Matched   int64
+	Modified  int64
+	Deleted   int64
+	// Retries is a number of retry attempts made by RetryPolicy
+	Retries int
+	Err     error
+}
+
+type operationCtxKey struct{}
+
+// SanitizedFilter() returns the filter with values replaced by SanitizedValue
+func (op *Operation) SanitizedFilter() interface{} {
+	return SanitizeFilter(op.Filter)
+}
+
+// ErrorClass() returns the error class of the operation
+func (op *Operation) ErrorClass() string {
+	return ErrorClass(op.Err)
+}
+
+// Instrumentation observes Storage calls, e.g. to record spans and metrics
+type Instrumentation interface {
+	// StartOperation() is called before the operation, the returned context is passed to the storage
+	StartOperation(ctx context.Context, op *Operation) context.Context
+	// FinishOperation() is called with results of the operation
+	FinishOperation(ctx context.Context, op *Operation)
+}
+
+var (
+	_ Instrumentation = InstrumentationFuncs{}
+)
+
+// InstrumentationFuncs implements Instrumentation with optional functions
+type InstrumentationFuncs struct {
+	Start  func(ctx context.Context, op *Operation) context.Context
+	Finish func(ctx context.Context, op *Operation)
+}
+
+// StartOperation() calls Start if it is set
+func (f InstrumentationFuncs) StartOperation(ctx context.Context, op *Operation) context.Context {
+	if f.Start == nil {
+		return ctx
+	}
+
+	return f.Start(ctx, op)
+}
+
+// FinishOperation() calls Finish if it is set
+func (f InstrumentationFuncs) FinishOperation(ctx context.Context, op *Operation) {
+	if f.Finish != nil {
+		f.Finish(ctx, op)
+	}
+}
+
+// ErrorClass() classifies the error for metrics,
+// an empty string is returned for nil
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrDocumentNotFound):
+		return ErrorClassNotFound
+	case errors.Is(err, ErrDocumentDuplication):
+		return ErrorClassDuplication
+	case errors.Is(err, ErrDocumentValidation), errors.Is(err, ErrInvalidPatch):
+		return ErrorClassValidation
+	case errors.Is(err, ErrTimeout):
+		return ErrorClassTimeout
+	case errors.Is(err, ErrNetwork):
+		return ErrorClassNetwork
+	default:
+		return ErrorClassOther
+	}
+}
+
+// SanitizeFilter() returns a copy of the filter where field names and operators are kept
+// and values are replaced by SanitizedValue, so the filter can be exported without user data
+func SanitizeFilter(filter interface{}) interface{} {
+	if filter == nil {
+		return nil
+	}
+
+	if fb, ok := filter.(*FilterBuilder); ok {
+		filter = fb.GetQuery()
+	}
+
+	switch f := filter.(type) {
+	case bson.M:
+		return sanitizeMap(f)
+	case map[string]interface{}:
+		return sanitizeMap(f)
+	case bson.D:
+		d := make(bson.D, len(f))
+		for i := range f {
+			d[i] = primitive.E{Key: f[i].Key, Value: SanitizeFilter(f[i].Value)}
+		}
+
+		return d
+	case bson.A:
+		return sanitizeSlice(f)
+	case []interface{}:
+		return sanitizeSlice(f)
+	}
+
+	// structs are sanitized through their BSON form
+	if v := reflect.ValueOf(filter); v.Kind() == reflect.Struct || (v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct) {
+		b, err := bson.Marshal(filter)
+		if err != nil {
+			return SanitizedValue
+		}
+
+		d := bson.D{}
+		if err := bson.Unmarshal(b, &d); err != nil {
+			return SanitizedValue
+		}
+
+		return SanitizeFilter(d)
+	}
+
+	return SanitizedValue
+}
+
+func sanitizeMap(m map[string]interface{}) bson.M {
+	sanitized := make(bson.M, len(m))
+	for k, v := range m {
+		sanitized[k] = SanitizeFilter(v)
+	}
+
+	return sanitized
+}
+
+func sanitizeSlice(a []interface{}) bson.A {
+	sanitized := make(bson.A, len(a))
+	for i := range a {
+		sanitized[i] = SanitizeFilter(a[i])
+	}
+
+	return sanitized
+}
+
+// sliceLen() returns a length of the slice or the slice pointer, 0 for other values
+func sliceLen(v interface{}) int64 {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Slice {
+		return 0
+	}
+
+	return int64(rv.Len())
+}
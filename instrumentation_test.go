@@ -0,0 +1,66 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("Instrumentation", func() {
+	Describe("SanitizeFilter()", func() {
+		It("should keep fields and operators", func() {
+			filter := bson.M{
+				"title": "secret",
+				"$or": bson.A{
+					bson.M{"age": bson.M{"$gte": 18}},
+					bson.D{{Key: "email", Value: "john@example.com"}},
+				},
+			}
+
+			Expect(SanitizeFilter(filter)).To(Equal(bson.M{
+				"title": SanitizedValue,
+				"$or": bson.A{
+					bson.M{"age": bson.M{"$gte": SanitizedValue}},
+					bson.D{{Key: "email", Value: SanitizedValue}},
+				},
+			}))
+		})
+
+		It("should sanitize filter builders and structs", func() {
+			fb := NewFilterBuilder().EqualTo("title", "secret")
+			Expect(SanitizeFilter(fb)).To(Equal(bson.M{"title": bson.M{"$eq": SanitizedValue}}))
+
+			Expect(SanitizeFilter(struct {
+				Title string `bson:"title"`
+			}{Title: "secret"})).To(Equal(bson.D{{Key: "title", Value: SanitizedValue}}))
+
+			Expect(SanitizeFilter(nil)).To(BeNil())
+		})
+	})
+
+	Describe("ErrorClass()", func() {
+		It("should classify errors", func() {
+			Expect(ErrorClass(nil)).To(Equal(""))
+			Expect(ErrorClass(ErrDocumentNotFound)).To(Equal(ErrorClassNotFound))
+			Expect(ErrorClass(fmt.Errorf("insert: %w", ErrDocumentDuplication))).To(Equal(ErrorClassDuplication))
+			Expect(ErrorClass(&TimeoutError{Err: context.DeadlineExceeded})).To(Equal(ErrorClassTimeout))
+			Expect(ErrorClass(errors.New("network"))).To(Equal(ErrorClassOther))
+		})
+	})
+
+	Describe("InstrumentationFuncs", func() {
+		It("should allow empty functions", func() {
+			in := InstrumentationFuncs{}
+			ctx := context.TODO()
+
+			Expect(in.StartOperation(ctx, &Operation{})).To(Equal(ctx))
+			Expect(func() { in.FinishOperation(ctx, &Operation{}) }).NotTo(Panic())
+		})
+	})
+})
@@ -0,0 +1,485 @@
+package mongol
+
+import (
+	"context"
+
+	timecop "github.com/bluele/go-timecop"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	_ Storage = (*InstrumentedCollection)(nil)
+)
+
+// InstrumentedCollection is a decorator of Storage which reports every operation to Instrumentation
+type InstrumentedCollection struct {
+	Storage
+	Instrumentation []Instrumentation
+}
+
+// NewInstrumentedCollection() is a constructor for InstrumentedCollection struct
+func NewInstrumentedCollection(s Storage, in ...Instrumentation) *InstrumentedCollection {
+	return &InstrumentedCollection{
+		Storage:         s,
+		Instrumentation: in,
+	}
+}
+
+// BSONRegistry() returns the codecs registry of the wrapped storage
+func (c *InstrumentedCollection) BSONRegistry() *bsoncodec.Registry {
+	return registryOf(c.Storage)
+}
+
+// DocumentID() returns the string form of the document key formatted by the wrapped storage
+func (c *InstrumentedCollection) DocumentID(m Document) string {
+	return documentIDOf(c.Storage, m)
+}
+
+// start() reports the beginning of the operation to all instrumentation in order of registration
+func (c *InstrumentedCollection) start(
+	ctx context.Context,
+	method string,
+	filter, doc, opts interface{},
+) (context.Context, *Operation) {
+	coll := c.Collection()
+
+	op := &Operation{
+		DBName:     coll.Database().Name(),
+		Collection: coll.Name(),
+		Method:     method,
+		Filter:     filter,
+		Document:   doc,
+		Options:    opts,
+		StartedAt:  timecop.Now(),
+	}
+
+	// BaseCollection reports retry attempts to the operation
+	ctx = context.WithValue(ctx, operationCtxKey{}, op)
+
+	for _, in := range c.Instrumentation {
+		ctx = in.StartOperation(ctx, op)
+	}
+
+	return ctx, op
+}
+
+// finish() reports results of the operation to all instrumentation in reverse order
+func (c *InstrumentedCollection) finish(ctx context.Context, op *Operation, err error) {
+	op.Duration = timecop.Now().Sub(op.StartedAt)
+	op.Err = err
+
+	for i := len(c.Instrumentation) - 1; i >= 0; i-- {
+		c.Instrumentation[i].FinishOperation(ctx, op)
+	}
+}
+
+func (op *Operation) setUpdateResult(res *mongo.UpdateResult) {
+	if res == nil {
+		return
+	}
+
+	op.Matched = res.MatchedCount
+	op.Modified = res.ModifiedCount
+}
+
+// reportUpdateResult() sets counters of the operation started by InstrumentedCollection,
+// it is used by storage methods which don't return an update result
+func reportUpdateResult(ctx context.Context, res *mongo.UpdateResult) {
+	if op, ok := ctx.Value(operationCtxKey{}).(*Operation); ok {
+		op.setUpdateResult(res)
+	}
+}
+
+func (op *Operation) setWriteResult(res *WriteResult) {
+	if res == nil {
+		return
+	}
+
+	op.Matched = res.Matched
+	op.Modified = res.Modified
+}
+
+func (op *Operation) setDeleteResult(res *mongo.DeleteResult) {
+	if res == nil {
+		return
+	}
+
+	op.Deleted = res.DeletedCount
+}
+
+// CreateIndex()
+func (c *InstrumentedCollection) CreateIndex(ctx context.Context, k interface{}, o *options.IndexOptions) (string, error) {
+	ctx, op := c.start(ctx, CreateIndexMethod, nil, k, o)
+
+	name, err := c.Storage.CreateIndex(ctx, k, o)
+	c.finish(ctx, op, err)
+
+	return name, err
+}
+
+// InsertOne()
+func (c *InstrumentedCollection) InsertOne(ctx context.Context, m Document, opts ...*options.InsertOneOptions) (string, error) {
+	ctx, op := c.start(ctx, InsertOneMethod, nil, m, opts)
+
+	id, err := c.Storage.InsertOne(ctx, m, opts...)
+	if err == nil {
+		op.Modified = 1
+	}
+
+	c.finish(ctx, op, err)
+
+	return id, err
+}
+
+// InsertMany()
+func (c *InstrumentedCollection) InsertMany(ctx context.Context, docs []interface{}, opts ...*options.InsertManyOptions) ([]string, error) {
+	ctx, op := c.start(ctx, InsertManyMethod, nil, docs, opts)
+
+	ids, err := c.Storage.InsertMany(ctx, docs, opts...)
+	op.Modified = int64(len(ids))
+	c.finish(ctx, op, err)
+
+	return ids, err
+}
+
+// BulkWrite()
+func (c *InstrumentedCollection) BulkWrite(ctx context.Context, b *BulkWriteBuilder) (*BulkWriteResult, error) {
+	ctx, op := c.start(ctx, BulkWriteMethod, nil, b, nil)
+
+	res, err := c.Storage.BulkWrite(ctx, b)
+	if res != nil {
+		op.Matched = res.MatchedCount
+		op.Modified = res.InsertedCount + res.ModifiedCount + res.UpsertedCount
+		op.Deleted = res.DeletedCount
+	}
+
+	c.finish(ctx, op, err)
+
+	return res, err
+}
+
+// UpdateOne()
+func (c *InstrumentedCollection) UpdateOne(ctx context.Context, m Document, opts ...*options.UpdateOptions) error {
+	ctx, op := c.start(ctx, UpdateOneMethod, bson.M{CollectionIDKey: documentIDOf(c.Storage, m)}, m, opts)
+
+	err := c.Storage.UpdateOne(ctx, m, opts...)
+	c.finish(ctx, op, err)
+
+	return err
+}
+
+// UpdateManyByFilter()
+func (c *InstrumentedCollection) UpdateManyByFilter(ctx context.Context, filter interface{}, m Document, opts ...*options.UpdateOptions) error {
+	ctx, op := c.start(ctx, UpdateManyByFilterMethod, filter, m, opts)
+
+	err := c.Storage.UpdateManyByFilter(ctx, filter, m, opts...)
+	c.finish(ctx, op, err)
+
+	return err
+}
+
+// PatchOneByID()
+func (c *InstrumentedCollection) PatchOneByID(ctx context.Context, recordID string, patch []byte, m Document) error {
+	// the patch is not reported, it may contain sensitive values
+	ctx, op := c.start(ctx, PatchOneByIDMethod, bson.M{CollectionIDKey: recordID}, nil, nil)
+
+	err := c.Storage.PatchOneByID(ctx, recordID, patch, m)
+	c.finish(ctx, op, err)
+
+	return err
+}
+
+// Save()
+func (c *InstrumentedCollection) Save(ctx context.Context, m Document, opts ...*options.UpdateOptions) error {
+	ctx, op := c.start(ctx, SaveMethod, bson.M{CollectionIDKey: documentIDOf(c.Storage, m)}, m, opts)
+
+	err := c.Storage.Save(ctx, m, opts...)
+	c.finish(ctx, op, err)
+
+	return err
+}
+
+// UpdateOneWithResult()
+func (c *InstrumentedCollection) UpdateOneWithResult(ctx context.Context, m Document, opts ...*options.UpdateOptions) (*WriteResult, error) {
+	ctx, op := c.start(ctx, UpdateOneWithResultMethod, bson.M{CollectionIDKey: documentIDOf(c.Storage, m)}, m, opts)
+
+	res, err := c.Storage.UpdateOneWithResult(ctx, m, opts...)
+	op.setWriteResult(res)
+	c.finish(ctx, op, err)
+
+	return res, err
+}
+
+// UpdateManyByFilterWithResult()
+func (c *InstrumentedCollection) UpdateManyByFilterWithResult(
+	ctx context.Context,
+	filter interface{},
+	m Document,
+	opts ...*options.UpdateOptions,
+) (*WriteResult, error) {
+	ctx, op := c.start(ctx, UpdateManyByFilterWithResultMethod, filter, m, opts)
+
+	res, err := c.Storage.UpdateManyByFilterWithResult(ctx, filter, m, opts...)
+	op.setWriteResult(res)
+	c.finish(ctx, op, err)
+
+	return res, err
+}
+
+// UpdateMany()
+func (c *InstrumentedCollection) UpdateMany(
+	ctx context.Context,
+	filter, update interface{},
+	opts ...*options.UpdateOptions,
+) (*mongo.UpdateResult, error) {
+	ctx, op := c.start(ctx, UpdateManyMethod, filter, update, opts)
+
+	res, err := c.Storage.UpdateMany(ctx, filter, update, opts...)
+	op.setUpdateResult(res)
+	c.finish(ctx, op, err)
+
+	return res, err
+}
+
+// UpsertOne()
+func (c *InstrumentedCollection) UpsertOne(ctx context.Context, filter interface{}, update bson.M, m Document) (Document, error) {
+	ctx, op := c.start(ctx, UpsertOneMethod, filter, update, nil)
+
+	doc, err := c.Storage.UpsertOne(ctx, filter, update, m)
+	if err == nil {
+		op.Documents = 1
+	}
+
+	c.finish(ctx, op, err)
+
+	return doc, err
+}
+
+// FindAndUpdateOne()
+func (c *InstrumentedCollection) FindAndUpdateOne(ctx context.Context, filter interface{}, update bson.M, m Document) (Document, error) {
+	ctx, op := c.start(ctx, FindAndUpdateOneMethod, filter, update, nil)
+
+	doc, err := c.Storage.FindAndUpdateOne(ctx, filter, update, m)
+	if err == nil {
+		op.Documents = 1
+	}
+
+	c.finish(ctx, op, err)
+
+	return doc, err
+}
+
+// ReplaceOne()
+func (c *InstrumentedCollection) ReplaceOne(
+	ctx context.Context,
+	filter interface{},
+	m Document,
+	opts ...*options.ReplaceOptions,
+) (*mongo.UpdateResult, error) {
+	ctx, op := c.start(ctx, ReplaceOneMethod, filter, m, opts)
+
+	res, err := c.Storage.ReplaceOne(ctx, filter, m, opts...)
+	op.setUpdateResult(res)
+	c.finish(ctx, op, err)
+
+	return res, err
+}
+
+// ReplaceOneByID()
+func (c *InstrumentedCollection) ReplaceOneByID(
+	ctx context.Context,
+	recordID string,
+	m Document,
+	opts ...*options.ReplaceOptions,
+) (*mongo.UpdateResult, error) {
+	ctx, op := c.start(ctx, ReplaceOneByIDMethod, bson.M{CollectionIDKey: recordID}, m, opts)
+
+	res, err := c.Storage.ReplaceOneByID(ctx, recordID, m, opts...)
+	op.setUpdateResult(res)
+	c.finish(ctx, op, err)
+
+	return res, err
+}
+
+// GetOneByID()
+func (c *InstrumentedCollection) GetOneByID(ctx context.Context, recordID string, m Document, opts ...*options.FindOneOptions) error {
+	ctx, op := c.start(ctx, GetOneByIDMethod, bson.M{CollectionIDKey: recordID}, nil, opts)
+
+	err := c.Storage.GetOneByID(ctx, recordID, m, opts...)
+	if err == nil {
+		op.Documents = 1
+	}
+
+	c.finish(ctx, op, err)
+
+	return err
+}
+
+// GetOneByFilter()
+func (c *InstrumentedCollection) GetOneByFilter(ctx context.Context, filter interface{}, m Document, opts ...*options.FindOneOptions) error {
+	ctx, op := c.start(ctx, GetOneByFilterMethod, filter, nil, opts)
+
+	err := c.Storage.GetOneByFilter(ctx, filter, m, opts...)
+	if err == nil {
+		op.Documents = 1
+	}
+
+	c.finish(ctx, op, err)
+
+	return err
+}
+
+// GetManyByFilter()
+func (c *InstrumentedCollection) GetManyByFilter(
+	ctx context.Context,
+	filter interface{},
+	modelBuilder func() Document,
+	opts ...*options.FindOptions,
+) ([]Document, error) {
+	ctx, op := c.start(ctx, GetManyByFilterMethod, filter, nil, opts)
+
+	docs, err := c.Storage.GetManyByFilter(ctx, filter, modelBuilder, opts...)
+	op.Documents = int64(len(docs))
+	c.finish(ctx, op, err)
+
+	return docs, err
+}
+
+// Iterate() reports the number of documents passed to fn
+func (c *InstrumentedCollection) Iterate(
+	ctx context.Context,
+	filter interface{},
+	modelBuilder func() Document,
+	fn func(Document) error,
+	opts ...*options.FindOptions,
+) error {
+	ctx, op := c.start(ctx, IterateMethod, filter, nil, opts)
+
+	err := c.Storage.Iterate(ctx, filter, modelBuilder, func(m Document) error {
+		op.Documents++
+		return fn(m)
+	}, opts...)
+	c.finish(ctx, op, err)
+
+	return err
+}
+
+// Iterator() reports the number of documents read by the iterator, the operation is finished by Close()
+func (c *InstrumentedCollection) Iterator(
+	ctx context.Context,
+	filter interface{},
+	modelBuilder func() Document,
+	opts ...*options.FindOptions,
+) (*Iterator, error) {
+	ctx, op := c.start(ctx, IterateMethod, filter, nil, opts)
+
+	it, err := c.Storage.Iterator(ctx, filter, modelBuilder, opts...)
+	if err != nil {
+		c.finish(ctx, op, err)
+		return nil, err
+	}
+
+	it.onClose = append(it.onClose, func(it *Iterator) {
+		op.Documents = it.docs
+		c.finish(ctx, op, it.err)
+	})
+
+	return it, nil
+}
+
+// FindAllByFilter()
+func (c *InstrumentedCollection) FindAllByFilter(
+	ctx context.Context,
+	filter interface{},
+	docs interface{},
+	opts ...*options.FindOptions,
+) error {
+	ctx, op := c.start(ctx, FindAllByFilterMethod, filter, nil, opts)
+
+	err := c.Storage.FindAllByFilter(ctx, filter, docs, opts...)
+	op.Documents = sliceLen(docs)
+	c.finish(ctx, op, err)
+
+	return err
+}
+
+// FindManyByFilter() reports the cursor creation, documents read from the cursor are not counted
+func (c *InstrumentedCollection) FindManyByFilter(
+	ctx context.Context,
+	filter interface{},
+	opts ...*options.FindOptions,
+) (*mongo.Cursor, error) {
+	ctx, op := c.start(ctx, FindManyByFilterMethod, filter, nil, opts)
+
+	cur, err := c.Storage.FindManyByFilter(ctx, filter, opts...)
+	c.finish(ctx, op, err)
+
+	return cur, err
+}
+
+// CountByFilter()
+func (c *InstrumentedCollection) CountByFilter(ctx context.Context, filter interface{}) (int64, error) {
+	ctx, op := c.start(ctx, CountByFilterMethod, filter, nil, nil)
+
+	count, err := c.Storage.CountByFilter(ctx, filter)
+	op.Matched = count
+	c.finish(ctx, op, err)
+
+	return count, err
+}
+
+// DeleteManyByFilter()
+func (c *InstrumentedCollection) DeleteManyByFilter(
+	ctx context.Context,
+	filter interface{},
+	opts ...*options.DeleteOptions,
+) (*mongo.DeleteResult, error) {
+	ctx, op := c.start(ctx, DeleteManyByFilterMethod, filter, nil, opts)
+
+	res, err := c.Storage.DeleteManyByFilter(ctx, filter, opts...)
+	op.setDeleteResult(res)
+	c.finish(ctx, op, err)
+
+	return res, err
+}
+
+// DeleteOneByID()
+func (c *InstrumentedCollection) DeleteOneByID(ctx context.Context, docID string) error {
+	ctx, op := c.start(ctx, DeleteOneByIDMethod, bson.M{CollectionIDKey: docID}, nil, nil)
+
+	err := c.Storage.DeleteOneByID(ctx, docID)
+	if err == nil {
+		op.Deleted = 1
+	}
+
+	c.finish(ctx, op, err)
+
+	return err
+}
+
+// DeleteOne()
+func (c *InstrumentedCollection) DeleteOne(ctx context.Context, m Document) error {
+	ctx, op := c.start(ctx, DeleteOneMethod, bson.M{CollectionIDKey: documentIDOf(c.Storage, m)}, nil, nil)
+
+	err := c.Storage.DeleteOne(ctx, m)
+	if err == nil {
+		op.Deleted = 1
+	}
+
+	c.finish(ctx, op, err)
+
+	return err
+}
+
+// DeleteAll()
+func (c *InstrumentedCollection) DeleteAll(ctx context.Context) error {
+	ctx, op := c.start(ctx, DeleteAllMethod, nil, nil, nil)
+
+	err := c.Storage.DeleteAll(ctx)
+	c.finish(ctx, op, err)
+
+	return err
+}
@@ -0,0 +1,109 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	. "github.com/wajox/mongol"
+)
+
+type spanCtxKey struct{}
+
+var _ = Describe("InstrumentedCollection", func() {
+	var (
+		base     *BaseCollection
+		doc      *ExampleModel
+		started  []string
+		finished []*Operation
+		in       InstrumentationFuncs
+	)
+
+	BeforeEach(func() {
+		base = newTestCollection("instrumented_test")
+
+		doc = NewExampleModel()
+		doc.ID = primitive.NewObjectID()
+
+		started = nil
+		finished = nil
+
+		in = InstrumentationFuncs{
+			Start: func(ctx context.Context, op *Operation) context.Context {
+				started = append(started, op.Method)
+				return context.WithValue(ctx, spanCtxKey{}, op.Method)
+			},
+			Finish: func(ctx context.Context, op *Operation) {
+				Expect(ctx.Value(spanCtxKey{})).To(Equal(op.Method))
+				finished = append(finished, op)
+			},
+		}
+	})
+
+	It("should report successful operations", func() {
+		storage := &memoryStorage{BaseCollection: base, docs: map[string]*ExampleModel{doc.GetHexID(): doc}}
+		instrumented := NewInstrumentedCollection(storage, in)
+
+		Expect(instrumented.GetOneByID(context.TODO(), doc.GetHexID(), &ExampleModel{})).To(BeNil())
+		Expect(instrumented.GetOneByID(context.TODO(), "missing", &ExampleModel{})).To(Equal(ErrDocumentNotFound))
+
+		Expect(started).To(Equal([]string{GetOneByIDMethod, GetOneByIDMethod}))
+		Expect(finished).To(HaveLen(2))
+
+		op := finished[0]
+		Expect(op.DBName).To(Equal("base_models_db_test"))
+		Expect(op.Collection).To(Equal("instrumented_test"))
+		Expect(op.Documents).To(Equal(int64(1)))
+		Expect(op.Err).To(BeNil())
+		Expect(op.SanitizedFilter()).To(Equal(bson.M{"_id": SanitizedValue}))
+
+		Expect(finished[1].Documents).To(Equal(int64(0)))
+		Expect(finished[1].ErrorClass()).To(Equal(ErrorClassNotFound))
+	})
+
+	// nolint
+	It("should report counters of updates", func() {
+		instrumented := NewInstrumentedCollection(base, in)
+
+		_, err := base.InsertOne(context.TODO(), doc)
+		Expect(err).To(BeNil())
+
+		doc.Title = "updated"
+		Expect(instrumented.UpdateOne(context.TODO(), doc)).To(BeNil())
+
+		Expect(finished).To(HaveLen(1))
+		Expect(finished[0].Method).To(Equal(UpdateOneMethod))
+		Expect(finished[0].Matched).To(Equal(int64(1)))
+		Expect(finished[0].Modified).To(Equal(int64(1)))
+	})
+
+	It("should report failed operations", func() {
+		base.SetTenancy(&Tenancy{Mode: TenantPerField})
+		instrumented := NewInstrumentedCollection(base, in)
+
+		_, err := instrumented.CountByFilter(context.TODO(), bson.M{"title": "secret"})
+		Expect(errors.Is(err, ErrTenantMissing)).To(BeTrue())
+
+		Expect(finished).To(HaveLen(1))
+		Expect(finished[0].Method).To(Equal(CountByFilterMethod))
+		Expect(errors.Is(finished[0].Err, ErrTenantMissing)).To(BeTrue())
+		Expect(finished[0].ErrorClass()).To(Equal(ErrorClassOther))
+	})
+
+	It("should report iterators failed to open", func() {
+		base.SetTenancy(&Tenancy{Mode: TenantPerField})
+		instrumented := NewInstrumentedCollection(base, in)
+
+		it, err := instrumented.Iterator(context.TODO(), bson.M{}, func() Document { return &ExampleModel{} })
+		Expect(it).To(BeNil())
+		Expect(errors.Is(err, ErrTenantMissing)).To(BeTrue())
+
+		Expect(finished).To(HaveLen(1))
+		Expect(finished[0].Method).To(Equal(IterateMethod))
+		Expect(errors.Is(finished[0].Err, ErrTenantMissing)).To(BeTrue())
+	})
+})
@@ -0,0 +1,206 @@
+package mongol
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Iterator streams documents of a cursor one by one, it is created by BaseCollection.Iterator()
+//
+//	it, err := storage.Iterator(ctx, filter, modelBuilder)
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//
+//	for it.Next() {
+//		m := it.Document().(*User)
+//	}
+//
+//	return it.Err()
+//
+// Unlike GetManyByFilter() reading of the cursor is limited by the context deadline only,
+// the Fetch timeout is not applied
+type Iterator struct {
+	s            *BaseCollection
+	ctx          context.Context
+	cur          *mongo.Cursor
+	modelBuilder func() Document
+	doc          Document
+	err          error
+	closed       bool
+	// docs is a number of decoded documents
+	docs int64
+	// onClose functions are called by Close(), e.g. to finish an instrumented operation
+	onClose []func(it *Iterator)
+}
+
+// Iterator() returns an iterator of documents matching the filter,
+// the iterator has to be closed, it holds an in-flight operation of the client until then
+func (s *BaseCollection) Iterator(
+	ctx context.Context,
+	filter interface{},
+	modelBuilder func() Document,
+	opts ...*options.FindOptions,
+) (_ *Iterator, err error) {
+	defer s.wrapError(&err, IterateMethod, "")
+
+	if err := s.runBeforeHooks(ctx, IterateMethod); err != nil {
+		return nil, err
+	}
+
+	// after hooks run on Close()
+	cur, err := s.findCursor(ctx, filter, opts)
+	if err != nil {
+		s.runAfterHooks(ctx, IterateMethod)
+		return nil, err
+	}
+
+	return &Iterator{
+		s:            s,
+		ctx:          ctx,
+		cur:          cur,
+		modelBuilder: modelBuilder,
+	}, nil
+}
+
+// Iterate() calls fn for every document matching the filter, the cursor is closed before return
+// Iteration stops on the first error of fn, return ErrStopIteration to stop it without an error
+func (s *BaseCollection) Iterate(
+	ctx context.Context,
+	filter interface{},
+	modelBuilder func() Document,
+	fn func(Document) error,
+	opts ...*options.FindOptions,
+) (err error) {
+	it, err := s.Iterator(ctx, filter, modelBuilder, opts...)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if closeErr := it.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	for it.Next() {
+		if err := fn(it.Document()); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	return it.Err()
+}
+
+// SetBatchSize() sets the number of documents loaded by one request of Iterate() and Iterator(),
+// BatchSize of FindOptions overrides it
+func (s *BaseCollection) SetBatchSize(n int32) {
+	s.BatchSize = n
+}
+
+// findCursor() opens a cursor with the collection batch size,
+// the Filter timeout limits the initial request only
+func (s *BaseCollection) findCursor(ctx context.Context, filter interface{}, opts []*options.FindOptions) (*mongo.Cursor, error) {
+	if s.BatchSize > 0 {
+		opts = append([]*options.FindOptions{options.Find().SetBatchSize(s.BatchSize)}, opts...)
+	}
+
+	filterCtx, filterCancel := withTimeout(ctx, s.timeouts(ctx).Filter)
+	defer filterCancel()
+
+	return s.FindManyByFilter(s.nested(filterCtx), filter, opts...)
+}
+
+// Next() decodes the next document, it returns false and closes the iterator
+// then the cursor is exhausted, the context is done or an error appears
+func (it *Iterator) Next() bool {
+	if it.closed || it.err != nil {
+		return false
+	}
+
+	it.doc = nil
+
+	if !it.cur.Next(it.ctx) {
+		if err := it.cur.Err(); err != nil {
+			it.setErr(HandleError(err))
+		}
+
+		it.Close()
+
+		return false
+	}
+
+	m := it.modelBuilder()
+	if err := it.cur.Decode(m); err != nil {
+		it.setErr(err)
+		return false
+	}
+
+	if err := setDecodedID(m, it.cur.Current); err != nil {
+		it.setErr(err)
+		return false
+	}
+
+	if err := runAfterFind(it.ctx, m); err != nil {
+		it.setErr(err)
+		return false
+	}
+
+	if err := trackChanges(it.s.BSONRegistry(), m); err != nil {
+		it.setErr(err)
+		return false
+	}
+
+	it.doc = m
+	it.docs++
+
+	return true
+}
+
+// Document() returns the document decoded by the last Next() call
+func (it *Iterator) Document() Document {
+	return it.doc
+}
+
+// Err() returns the error stopped the iteration
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close() closes the cursor and runs after hooks, it is safe to call it several times
+// The cursor is closed even if the context is done, so the server releases it
+func (it *Iterator) Close() error {
+	if it.closed {
+		return nil
+	}
+
+	it.closed = true
+
+	defer it.s.runAfterHooks(it.ctx, IterateMethod)
+
+	closeCtx, closeCancel := withTimeout(context.Background(), it.s.timeouts(it.ctx).CloseCursor)
+	defer closeCancel()
+
+	if err := it.cur.Close(closeCtx); err != nil && it.err == nil {
+		it.setErr(HandleError(err))
+	}
+
+	for _, fn := range it.onClose {
+		fn(it)
+	}
+
+	return it.err
+}
+
+func (it *Iterator) setErr(err error) {
+	it.s.wrapError(&err, IterateMethod, "")
+	it.err = err
+}
@@ -0,0 +1,255 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/wajox/mongol"
+)
+
+// iteratingStorage passes configured documents to Iterate() callbacks
+type iteratingStorage struct {
+	*BaseCollection
+	docs []Document
+	err  error
+}
+
+func (s *iteratingStorage) Iterate(
+	ctx context.Context,
+	filter interface{},
+	modelBuilder func() Document,
+	fn func(Document) error,
+	opts ...*options.FindOptions,
+) error {
+	for _, m := range s.docs {
+		if err := fn(m); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	return s.err
+}
+
+var _ = Describe("Iterator", func() {
+	var (
+		storage *BaseCollection
+	)
+
+	BeforeEach(func() {
+		storage = newTestCollection("iterator_test")
+	})
+
+	Describe("SetBatchSize()", func() {
+		It("should set the batch size", func() {
+			storage.SetBatchSize(10)
+			Expect(storage.BatchSize).To(Equal(int32(10)))
+		})
+	})
+
+	Describe("CircuitBreaker.Iterate()", func() {
+		var (
+			iterating *iteratingStorage
+			breaker   *CircuitBreaker
+		)
+
+		BeforeEach(func() {
+			iterating = &iteratingStorage{
+				BaseCollection: storage,
+				docs:           []Document{NewExampleModel(), NewExampleModel()},
+			}
+
+			breaker = NewCircuitBreaker(iterating)
+			breaker.ConsecutiveFailures = 1
+		})
+
+		It("should not count errors of the callback as failures", func() {
+			fnErr := errors.New("callback failed")
+
+			err := breaker.Iterate(context.TODO(), bson.M{}, nil, func(m Document) error {
+				return fnErr
+			})
+			Expect(err).To(Equal(fnErr))
+			Expect(breaker.State()).To(Equal(CircuitClosed))
+
+			count := 0
+			err = breaker.Iterate(context.TODO(), bson.M{}, nil, func(m Document) error {
+				count++
+				return ErrStopIteration
+			})
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(1))
+			Expect(breaker.State()).To(Equal(CircuitClosed))
+
+			err = breaker.Iterate(context.TODO(), bson.M{}, nil, func(m Document) error {
+				return fmt.Errorf("done: %w", ErrStopIteration)
+			})
+			Expect(err).To(BeNil())
+			Expect(breaker.State()).To(Equal(CircuitClosed))
+		})
+
+		It("should count errors of the storage as failures", func() {
+			iterating.err = errors.New("connection refused")
+
+			err := breaker.Iterate(context.TODO(), bson.M{}, nil, func(m Document) error { return nil })
+			Expect(err).To(Equal(iterating.err))
+			Expect(breaker.State()).To(Equal(CircuitOpen))
+		})
+	})
+
+	// nolint
+	Describe("Iterate()", func() {
+		modelBuilder := func() Document { return &ExampleModel{} }
+
+		BeforeEach(func() {
+			storage.DeleteAll(context.TODO())
+
+			docs := make([]interface{}, 5)
+			for i := range docs {
+				docs[i] = NewExampleModel()
+			}
+
+			_, err := storage.InsertMany(context.TODO(), docs)
+			Expect(err).To(BeNil())
+
+			storage.SetBatchSize(2)
+		})
+
+		AfterEach(func() {
+			storage.DeleteAll(context.TODO())
+		})
+
+		It("should pass all documents to the callback", func() {
+			var ids []string
+
+			err := storage.Iterate(context.TODO(), bson.M{}, modelBuilder, func(m Document) error {
+				Expect(m.GetHexID()).NotTo(BeEmpty())
+				ids = append(ids, m.GetHexID())
+				return nil
+			})
+			Expect(err).To(BeNil())
+			Expect(ids).To(HaveLen(5))
+		})
+
+		It("should stop on ErrStopIteration", func() {
+			count := 0
+
+			err := storage.Iterate(context.TODO(), bson.M{}, modelBuilder, func(m Document) error {
+				count++
+				if count == 3 {
+					return ErrStopIteration
+				}
+
+				return nil
+			})
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(3))
+		})
+
+		It("should stop on wrapped ErrStopIteration", func() {
+			count := 0
+
+			err := storage.Iterate(context.TODO(), bson.M{}, modelBuilder, func(m Document) error {
+				count++
+				return fmt.Errorf("found %s: %w", m.GetHexID(), ErrStopIteration)
+			})
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(1))
+		})
+
+		It("should return the error of the callback", func() {
+			fnErr := errors.New("callback failed")
+
+			err := storage.Iterate(context.TODO(), bson.M{}, modelBuilder, func(m Document) error {
+				return fnErr
+			})
+			Expect(err).To(Equal(fnErr))
+		})
+
+		It("should honor the context deadline", func() {
+			ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+			defer cancel()
+
+			err := storage.Iterate(ctx, bson.M{}, modelBuilder, func(m Document) error {
+				<-ctx.Done()
+				return nil
+			})
+			Expect(errors.Is(err, ErrTimeout)).To(BeTrue())
+		})
+	})
+
+	// nolint
+	Describe("Iterator()", func() {
+		BeforeEach(func() {
+			storage.DeleteAll(context.TODO())
+
+			_, err := storage.InsertMany(context.TODO(), []interface{}{NewExampleModel(), NewExampleModel()})
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			storage.DeleteAll(context.TODO())
+		})
+
+		It("should stream documents", func() {
+			it, err := storage.Iterator(context.TODO(), bson.M{}, func() Document { return &ExampleModel{} })
+			Expect(err).To(BeNil())
+
+			count := 0
+			for it.Next() {
+				Expect(it.Document()).To(BeAssignableToTypeOf(&ExampleModel{}))
+				count++
+			}
+
+			Expect(it.Err()).To(BeNil())
+			Expect(count).To(Equal(2))
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Close()).To(BeNil())
+		})
+
+		It("should be closed early", func() {
+			it, err := storage.Iterator(context.TODO(), bson.M{}, func() Document { return &ExampleModel{} })
+			Expect(err).To(BeNil())
+
+			Expect(it.Next()).To(BeTrue())
+			Expect(it.Close()).To(BeNil())
+			Expect(it.Close()).To(BeNil())
+			Expect(it.Next()).To(BeFalse())
+		})
+
+		It("should be reported by InstrumentedCollection on Close()", func() {
+			var finished []*Operation
+
+			instrumented := NewInstrumentedCollection(storage, InstrumentationFuncs{
+				Finish: func(ctx context.Context, op *Operation) {
+					finished = append(finished, op)
+				},
+			})
+
+			it, err := instrumented.Iterator(context.TODO(), bson.M{}, func() Document { return &ExampleModel{} })
+			Expect(err).To(BeNil())
+
+			for it.Next() {
+				Expect(finished).To(BeEmpty())
+			}
+
+			Expect(it.Close()).To(BeNil())
+			Expect(it.Close()).To(BeNil())
+
+			Expect(finished).To(HaveLen(1))
+			Expect(finished[0].Method).To(Equal(IterateMethod))
+			Expect(finished[0].Documents).To(Equal(int64(2)))
+			Expect(finished[0].Err).To(BeNil())
+		})
+	})
+})
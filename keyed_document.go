@@ -0,0 +1,152 @@
+package mongol
+
+import (
+	"time"
+
+	timecop "github.com/bluele/go-timecop"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// KeyedDocument is implemented by documents whose _id is not an ObjectID
+// BaseCollection uses GetKey() and SetKey() instead of GetID() and SetHexID() for such documents
+type KeyedDocument interface {
+	Document
+	GetKey() interface{}
+	SetKey(key interface{}) error
+}
+
+var (
+	_ KeyedDocument = (*UUIDDocument)(nil)
+	_ KeyedDocument = (*StringDocument)(nil)
+	_ KeyedDocument = (*Int64Document)(nil)
+)
+
+// Timestamps
+type Timestamps struct {
+	CreatedAt time.Time `json:"created_at" bson:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at,omitempty"`
+}
+
+// SetupCreatedAt() sets CreatedAt field for the model
+func (t *Timestamps) SetupCreatedAt() {
+	t.CreatedAt = timecop.Now().UTC()
+}
+
+// SetupUpdatedAt() sets UpdatedAt field for the model
+func (t *Timestamps) SetupUpdatedAt() {
+	t.UpdatedAt = timecop.Now().UTC()
+}
+
+// StringDocument is a base document keyed by string, use it with StringIDStrategy
+type StringDocument struct {
+	ID         string `json:"id,omitempty" bson:"_id,omitempty"`
+	Timestamps `bson:",inline"`
+}
+
+// GetID() returns NilObjectID, use GetKey() instead
+func (m *StringDocument) GetID() primitive.ObjectID {
+	return primitive.NilObjectID
+}
+
+// GetHexID() returns ID of the document
+func (m *StringDocument) GetHexID() string {
+	return m.ID
+}
+
+// SetHexID() sets ID of the document
+func (m *StringDocument) SetHexID(id string) error {
+	m.ID = id
+
+	return nil
+}
+
+// SetJSONID() does nothing because the ID is decoded with the document
+func (m *StringDocument) SetJSONID([]byte) error {
+	return nil
+}
+
+// GetKey() returns ID of the document
+func (m *StringDocument) GetKey() interface{} {
+	return m.ID
+}
+
+// SetKey() sets ID of the document
+func (m *StringDocument) SetKey(key interface{}) error {
+	id, ok := key.(string)
+	if !ok {
+		return ErrInvalidID
+	}
+
+	m.ID = id
+
+	return nil
+}
+
+// UUIDDocument is a base document keyed by UUID string, use it with UUIDStrategy
+type UUIDDocument struct {
+	StringDocument `bson:",inline"`
+}
+
+// SetHexID() sets ID of the document from UUID string
+func (m *UUIDDocument) SetHexID(id string) error {
+	key, err := UUIDStrategy{}.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	return m.SetKey(key)
+}
+
+// Int64Document is a base document keyed by int64, use it with Int64IDStrategy
+type Int64Document struct {
+	ID         int64 `json:"id,omitempty" bson:"_id,omitempty"`
+	Timestamps `bson:",inline"`
+}
+
+// GetID() returns NilObjectID, use GetKey() instead
+func (m *Int64Document) GetID() primitive.ObjectID {
+	return primitive.NilObjectID
+}
+
+// GetHexID() returns ID of the document as a decimal string
+func (m *Int64Document) GetHexID() string {
+	id, _ := Int64IDStrategy{}.Format(m.ID)
+
+	return id
+}
+
+// SetHexID() sets ID of the document from a decimal string
+func (m *Int64Document) SetHexID(id string) error {
+	key, err := Int64IDStrategy{}.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	return m.SetKey(key)
+}
+
+// SetJSONID() does nothing because the ID is decoded with the document
+func (m *Int64Document) SetJSONID([]byte) error {
+	return nil
+}
+
+// GetKey() returns ID of the document
+func (m *Int64Document) GetKey() interface{} {
+	return m.ID
+}
+
+// SetKey() sets ID of the document
+func (m *Int64Document) SetKey(key interface{}) error {
+	switch v := key.(type) {
+	case int64:
+		m.ID = v
+	case int32:
+		m.ID = int64(v)
+	case int:
+		m.ID = int64(v)
+	default:
+		return ErrInvalidID
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	. "github.com/wajox/mongol"
+)
+
+type UUIDModel struct {
+	UUIDDocument `bson:",inline"`
+
+	Title string `json:"title,omitempty" bson:"title,omitempty"`
+}
+
+type CompositeKey struct {
+	Tenant string `bson:"tenant"`
+	Number int64  `bson:"number"`
+}
+
+// CompositeKeyModel is keyed by a composite key, its GetHexID() is not aware of the key
+type CompositeKeyModel struct {
+	ID         CompositeKey `bson:"_id"`
+	Timestamps `bson:",inline"`
+}
+
+func (m *CompositeKeyModel) GetID() primitive.ObjectID    { return primitive.NilObjectID }
+func (m *CompositeKeyModel) GetHexID() string             { return "" }
+func (m *CompositeKeyModel) SetHexID(string) error        { return nil }
+func (m *CompositeKeyModel) SetJSONID([]byte) error       { return nil }
+func (m *CompositeKeyModel) GetKey() interface{}          { return m.ID }
+func (m *CompositeKeyModel) SetKey(key interface{}) error { return nil }
+
+var _ = Describe("KeyedDocument", func() {
+	Describe("UUIDDocument", func() {
+		It("should set an ID from string", func() {
+			id := uuid.New().String()
+			doc := &UUIDDocument{}
+
+			Expect(doc.SetHexID(id)).To(BeNil())
+			Expect(doc.GetHexID()).To(Equal(id))
+			Expect(doc.GetKey()).To(Equal(id))
+			Expect(doc.SetHexID("123")).To(Equal(ErrInvalidID))
+		})
+	})
+
+	Describe("Int64Document", func() {
+		It("should set an ID from string", func() {
+			doc := &Int64Document{}
+
+			Expect(doc.SetHexID("42")).To(BeNil())
+			Expect(doc.GetKey()).To(Equal(int64(42)))
+			Expect(doc.GetHexID()).To(Equal("42"))
+			Expect(doc.SetKey("42")).To(Equal(ErrInvalidID))
+		})
+	})
+
+	Describe("BaseCollection.DocumentID()", func() {
+		It("should format the key by IDStrategy", func() {
+			storage := newTestCollection("keyed_test")
+
+			doc := NewExampleModel()
+			doc.ID = primitive.NewObjectID()
+			Expect(storage.DocumentID(doc)).To(Equal(doc.ID.Hex()))
+			Expect(storage.DocumentID(NewExampleModel())).To(BeEmpty())
+
+			storage.SetIDStrategy(NewCompositeIDStrategy("tenant", "number").WithParts(nil, Int64IDStrategy{}))
+			order := &CompositeKeyModel{ID: CompositeKey{Tenant: "acme", Number: 42}}
+			Expect(storage.DocumentID(order)).To(Equal("acme:42"))
+
+			cached := NewCachedCollection(NewInstrumentedCollection(storage), nil, 0)
+			Expect(cached.DocumentID(order)).To(Equal("acme:42"))
+		})
+	})
+
+	// nolint
+	Describe("BaseCollection with UUIDStrategy", func() {
+		var (
+			storage *BaseCollection
+		)
+
+		BeforeEach(func() {
+			storage = newTestCollection("uuid_models_test")
+
+			storage.SetIDStrategy(UUIDStrategy{})
+		})
+
+		Context("with invalid ID", func() {
+			It("should return ErrInvalidID", func() {
+				Expect(errors.Is(storage.GetOneByID(context.TODO(), "123", &UUIDModel{}), ErrInvalidID)).To(BeTrue())
+				Expect(errors.Is(storage.DeleteOneByID(context.TODO(), "123"), ErrInvalidID)).To(BeTrue())
+			})
+		})
+
+		Describe("methods", func() {
+			AfterEach(func() {
+				storage.DeleteAll(context.TODO())
+			})
+
+			It("should insert, find, replace and delete the document", func() {
+				m := &UUIDModel{Title: "Title"}
+
+				id, err := storage.InsertOne(context.TODO(), m)
+				Expect(err).To(BeNil())
+				Expect(id).To(Equal(m.ID))
+				Expect(IsValidMongoID(id)).To(BeFalse())
+
+				found := &UUIDModel{}
+				Expect(storage.GetOneByID(context.TODO(), id, found)).To(BeNil())
+				Expect(found.Title).To(Equal("Title"))
+
+				found.Title = "New title"
+				res, err := storage.ReplaceOneByID(context.TODO(), id, found)
+				Expect(err).To(BeNil())
+				Expect(res.ModifiedCount).To(Equal(int64(1)))
+
+				found.Title = "Updated title"
+				Expect(storage.UpdateOne(context.TODO(), found)).To(BeNil())
+
+				ids, err := storage.InsertMany(context.TODO(), []interface{}{&UUIDModel{Title: "Other"}})
+				Expect(err).To(BeNil())
+				Expect(len(ids)).To(Equal(1))
+
+				count, err := storage.CountByFilter(context.TODO(), bson.M{})
+				Expect(err).To(BeNil())
+				Expect(count).To(Equal(int64(2)))
+
+				Expect(storage.DeleteOneByID(context.TODO(), id)).To(BeNil())
+				Expect(errors.Is(storage.GetOneByID(context.TODO(), id, &UUIDModel{}), ErrDocumentNotFound)).To(BeTrue())
+			})
+		})
+	})
+})
@@ -1,13 +1,36 @@
 package mongol_test
 
 import (
+	"context"
+	"os"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	. "github.com/wajox/mongol"
 )
 
+const testDBName = "base_models_db_test"
+
 func TestMongol(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Mongol Suite")
 }
+
+// testMongoURI() returns MONGODB_URI or the address of the local server
+func testMongoURI() string {
+	if mongoURI := os.Getenv("MONGODB_URI"); mongoURI != "" {
+		return mongoURI
+	}
+
+	return "mongodb://0.0.0.0:27017"
+}
+
+// newTestCollection() returns the collection of the test database
+func newTestCollection(collectionName string) *BaseCollection {
+	storage, err := NewBaseCollection(context.TODO(), testMongoURI(), testDBName, collectionName)
+	Expect(err).To(BeNil())
+
+	return storage
+}
@@ -0,0 +1,72 @@
+package mongol
+
+import (
+	"reflect"
+)
+
+// OperationError wraps errors of BaseCollection methods with the operation context,
+// errors.Is() and errors.As() see the wrapped error, e.g. ErrDocumentNotFound
+type OperationError struct {
+	Collection string
+	// Method is one of *Method constants, nested calls of the collection keep the outermost method
+	Method string
+	// ID of the document if the operation is called for a single document
+	ID  string
+	Err error
+}
+
+// Error()
+func (e *OperationError) Error() string {
+	if e.ID == "" {
+		return e.Collection + "." + e.Method + ": " + e.Err.Error()
+	}
+
+	return e.Collection + "." + e.Method + "(" + e.ID + "): " + e.Err.Error()
+}
+
+// Unwrap() returns the error of the operation
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// wrapError() wraps *errp with *OperationError, id is a string or a Document
+// whose key is read when the method returns
+func (s *BaseCollection) wrapError(errp *error, method string, id interface{}) {
+	if *errp == nil {
+		return
+	}
+
+	docID := s.operationID(id)
+
+	// errors of nested calls are reported by the outermost method
+	if opErr, ok := (*errp).(*OperationError); ok && opErr.Collection == s.CollectionName {
+		opErr.Method = method
+		if opErr.ID == "" {
+			opErr.ID = docID
+		}
+
+		return
+	}
+
+	*errp = &OperationError{
+		Collection: s.CollectionName,
+		Method:     method,
+		ID:         docID,
+		Err:        *errp,
+	}
+}
+
+func (s *BaseCollection) operationID(id interface{}) string {
+	switch v := id.(type) {
+	case string:
+		return v
+	case Document:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return ""
+		}
+
+		return s.DocumentID(v)
+	default:
+		return ""
+	}
+}
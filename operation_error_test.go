@@ -0,0 +1,57 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("OperationError", func() {
+	var (
+		storage *BaseCollection
+	)
+
+	BeforeEach(func() {
+		storage = newTestCollection("operation_error_test")
+	})
+
+	It("should wrap errors with the operation context", func() {
+		err := storage.DeleteOneByID(context.TODO(), "123")
+
+		var opErr *OperationError
+		Expect(errors.As(err, &opErr)).To(BeTrue())
+		Expect(opErr.Collection).To(Equal("operation_error_test"))
+		Expect(opErr.Method).To(Equal(DeleteOneByIDMethod))
+		Expect(opErr.ID).To(Equal("123"))
+		Expect(errors.Is(err, ErrInvalidObjectID)).To(BeTrue())
+		Expect(err.Error()).To(Equal("operation_error_test.DeleteOneByID(123): " + ErrInvalidObjectID.Error()))
+	})
+
+	It("should report the outermost method of nested calls", func() {
+		storage.SetTenancy(&Tenancy{Mode: TenantPerField})
+
+		m := NewExampleModel()
+		err := storage.GetOneByID(context.TODO(), "5f2b7e5c0000000000000000", m)
+
+		var opErr *OperationError
+		Expect(errors.As(err, &opErr)).To(BeTrue())
+		Expect(opErr.Method).To(Equal(GetOneByIDMethod))
+		Expect(opErr.ID).To(Equal("5f2b7e5c0000000000000000"))
+		Expect(opErr.Err).To(Equal(ErrTenantMissing))
+	})
+
+	It("should wrap errors of hooks", func() {
+		hookErr := errors.New("forbidden")
+		storage.AddBeforeHook(CountByFilterMethod, func(ctx context.Context) error {
+			return hookErr
+		})
+
+		_, err := storage.CountByFilter(context.TODO(), nil)
+		Expect(errors.Is(err, hookErr)).To(BeTrue())
+		Expect(err.Error()).To(Equal("operation_error_test.CountByFilter: forbidden"))
+	})
+})
@@ -0,0 +1,644 @@
+package mongol
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	timecop "github.com/bluele/go-timecop"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// operations of RFC 6902 JSON Patch
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+	PatchOpMove    = "move"
+	PatchOpCopy    = "copy"
+	PatchOpTest    = "test"
+	// PatchOpMerge is reported by PatchError for RFC 7396 JSON Merge Patch
+	PatchOpMerge = "merge"
+
+	updatedAtKey = "updated_at"
+)
+
+var (
+	errPatchInvalidKey    = errors.New("invalid key")
+	errPatchScalarParent  = errors.New("field has no nested fields")
+	errPatchIDField       = errors.New("ID can not be patched")
+	errPatchConflict      = errors.New("path conflicts with another operation")
+	errPatchUnsupported   = errors.New("operation is not supported")
+	errPatchMissingValue  = errors.New("value is missing")
+	errPatchTypeMismatch  = errors.New("types of the fields do not match")
+	errPatchArrayElement  = errors.New("array elements can not be removed or moved")
+	errPatchInvalidFormat = errors.New("patch must be a JSON object or a JSON array")
+)
+
+// PatchError appears then the patch can not be applied to the model,
+// errors.Is(err, ErrInvalidPatch) is true for it
+type PatchError struct {
+	Op   string
+	Path string
+	Err  error
+}
+
+// Error()
+func (e *PatchError) Error() string {
+	if e.Op == "" {
+		return ErrInvalidPatch.Error() + ": " + e.Err.Error()
+	}
+
+	return ErrInvalidPatch.Error() + ": " + e.Op + " " + e.Path + ": " + e.Err.Error()
+}
+
+// Is() matches ErrInvalidPatch
+func (e *PatchError) Is(target error) bool {
+	return target == ErrInvalidPatch
+}
+
+// Unwrap() returns the cause of the error
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// PatchOneByID() applies the patch to the document with given ID and decodes the updated document into m
+// RFC 7396 JSON Merge Patch (JSON object) and RFC 6902 JSON Patch (JSON array) are supported,
+// JSON fields of the patch are translated to BSON fields of the model and values are validated
+// by decoding them into the types of the model fields
+// JSON Patch operations are translated to $set, $unset, $push and $rename,
+// copy and removal of array elements are not supported, failed test operations and replace, remove
+// or move of missing fields return ErrPatchTestFailed
+// Patches adding array elements are not retried by RetryPolicy as $push is not idempotent
+func (s *BaseCollection) PatchOneByID(ctx context.Context, recordID string, patch []byte, m Document) (err error) {
+	defer s.wrapError(&err, PatchOneByIDMethod, recordID)
+
+	if err := s.runBeforeHooks(ctx, PatchOneByIDMethod); err != nil {
+		return err
+	}
+	defer s.runAfterHooks(ctx, PatchOneByIDMethod)
+
+	key, err := s.idStrategy().Parse(recordID)
+	if err != nil {
+		return err
+	}
+
+	u, err := parsePatch(patch, reflect.TypeOf(m))
+	if err != nil {
+		return err
+	}
+
+	if ts, ok := updatedAt(reflect.TypeOf(m)); ok && u.conflict(updatedAtKey) == nil {
+		u.set = append(u.set, primitive.E{Key: updatedAtKey, Value: ts})
+	}
+
+	filter := bson.D{{Key: CollectionIDKey, Value: key}}
+	filter = append(filter, u.test...)
+
+	if update := u.update(); len(update) > 0 {
+		err = s.findAndPatch(ctx, filter, update, m, len(u.push) == 0)
+	} else {
+		err = s.GetOneByFilter(s.nested(ctx), filter, m)
+	}
+
+	if errors.Is(err, ErrDocumentNotFound) && len(u.test) > 0 {
+		// the document exists, so one of the test operations failed
+		if n, countErr := s.CountByFilter(s.nested(ctx), bson.M{CollectionIDKey: key}); countErr == nil && n > 0 {
+			return ErrPatchTestFailed
+		}
+	}
+
+	return err
+}
+
+// ParsePatch() translates the patch to the update document of the model like PatchOneByID() does,
+// test is a filter with conditions of JSON Patch test operations and existence of replaced,
+// removed and moved fields
+func ParsePatch(patch []byte, model interface{}) (update, test bson.D, err error) {
+	u, err := parsePatch(patch, reflect.TypeOf(model))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return u.update(), u.test, nil
+}
+
+// findAndPatch() updates the document and decodes the result into m,
+// the update is retried by RetryPolicy if it is idempotent
+func (s *BaseCollection) findAndPatch(ctx context.Context, filter interface{}, update bson.D, m Document, idempotent bool) error {
+	coll, filter, err := s.scope(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var b bson.Raw
+
+	patch := func(ctx context.Context) (err error) {
+		queryCtx, queryCancel := withTimeout(ctx, s.timeouts(ctx).Query)
+		defer queryCancel()
+
+		res := coll.FindOneAndUpdate(queryCtx, filter, update, opts)
+		if err = res.Decode(m); err != nil {
+			return err
+		}
+
+		b, err = res.DecodeBytes()
+		return err
+	}
+
+	if idempotent {
+		err = s.retry(ctx, PatchOneByIDMethod, true, patch)
+	} else {
+		err = patch(ctx)
+	}
+
+	if err == mongo.ErrNoDocuments {
+		return ErrDocumentNotFound
+	}
+
+	if err != nil {
+		return HandleError(err)
+	}
+
+	if err := setDecodedID(m, b); err != nil {
+		return err
+	}
+
+	if err := runAfterFind(ctx, m); err != nil {
+		return err
+	}
+
+	return trackChanges(s.BSONRegistry(), m)
+}
+
+// updatedAt() returns the current time for updated_at field of the model like SetupUpdatedAt() does,
+// false is returned if the model has no such field of time.Time or primitive.DateTime type
+func updatedAt(t reflect.Type) (interface{}, bool) {
+	ft, ok := bsonFieldType(t, updatedAtKey)
+	if !ok {
+		return nil, false
+	}
+
+	now := timecop.Now().UTC()
+
+	switch ft {
+	case reflect.TypeOf(time.Time{}):
+		return now, true
+	case reflect.TypeOf(primitive.DateTime(0)):
+		return primitive.NewDateTimeFromTime(now), true
+	default:
+		return nil, false
+	}
+}
+
+// bsonFieldType() returns the type of the struct field by BSON key, fields of inline structs are included
+func bsonFieldType(t reflect.Type, key string) (reflect.Type, bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		fieldKey, inline, skip := bsonFieldKey(f)
+
+		switch {
+		case skip:
+		case inline:
+			if ft, ok := bsonFieldType(f.Type, key); ok {
+				return ft, true
+			}
+		case fieldKey == key:
+			return f.Type, true
+		}
+	}
+
+	return nil, false
+}
+
+// patchUpdate collects update operators of the patch
+type patchUpdate struct {
+	set    bson.D
+	unset  bson.D
+	push   bson.D
+	rename bson.D
+	// test is a filter of RFC 6902 test operations
+	test  bson.D
+	paths []string
+}
+
+// update() returns the update document
+func (u *patchUpdate) update() bson.D {
+	update := bson.D{}
+
+	for _, op := range []primitive.E{
+		{Key: "$set", Value: u.set},
+		{Key: "$unset", Value: u.unset},
+		{Key: "$push", Value: u.push},
+		{Key: "$rename", Value: u.rename},
+	} {
+		if len(op.Value.(bson.D)) > 0 {
+			update = append(update, op)
+		}
+	}
+
+	return update
+}
+
+// conflict() returns an error if the path or its parent or child is already updated,
+// MongoDB rejects such updates
+func (u *patchUpdate) conflict(path string) error {
+	for _, p := range u.paths {
+		if p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(path, p+".") {
+			return errPatchConflict
+		}
+	}
+
+	return nil
+}
+
+// add() appends the field to the update operator
+func (u *patchUpdate) add(op *bson.D, path string, value interface{}) error {
+	if err := u.conflict(path); err != nil {
+		return err
+	}
+
+	u.paths = append(u.paths, path)
+	*op = append(*op, primitive.E{Key: path, Value: value})
+
+	return nil
+}
+
+// parsePatch() detects the format of the patch and translates it to the update
+func parsePatch(patch []byte, t reflect.Type) (*patchUpdate, error) {
+	patch = bytes.TrimSpace(patch)
+	u := &patchUpdate{}
+
+	switch {
+	case len(patch) > 0 && patch[0] == '{':
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(patch, &obj); err != nil {
+			return nil, &PatchError{Err: err}
+		}
+
+		return u, u.merge(t, "", "", obj)
+	case len(patch) > 0 && patch[0] == '[':
+		var ops []jsonPatchOperation
+		if err := json.Unmarshal(patch, &ops); err != nil {
+			return nil, &PatchError{Err: err}
+		}
+
+		for i := range ops {
+			if err := u.apply(t, ops[i]); err != nil {
+				return nil, &PatchError{Op: ops[i].Op, Path: ops[i].Path, Err: err}
+			}
+		}
+
+		return u, nil
+	default:
+		return nil, &PatchError{Err: errPatchInvalidFormat}
+	}
+}
+
+// merge() translates RFC 7396 JSON Merge Patch object,
+// nested objects are merged field by field, null removes the field
+func (u *patchUpdate) merge(t reflect.Type, pointer, prefix string, obj map[string]json.RawMessage) error {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fieldPointer := pointer + "/" + k
+
+		field, err := patchStep(t, k)
+		if err == nil && prefix == "" && field.key == CollectionIDKey {
+			err = errPatchIDField
+		}
+
+		if err != nil {
+			return &PatchError{Op: PatchOpMerge, Path: fieldPointer, Err: err}
+		}
+
+		path := prefix + field.key
+		raw := bytes.TrimSpace(obj[k])
+
+		switch {
+		case bytes.Equal(raw, []byte("null")):
+			err = u.add(&u.unset, path, "")
+		case len(raw) > 0 && raw[0] == '{' && isPatchContainer(field.typ):
+			var nested map[string]json.RawMessage
+			if err = json.Unmarshal(raw, &nested); err == nil {
+				err = u.merge(field.typ, fieldPointer, path+".", nested)
+			}
+		default:
+			var v interface{}
+			if v, err = decodePatchValue(raw, field.typ); err == nil {
+				err = u.add(&u.set, path, v)
+			}
+		}
+
+		if err != nil {
+			var pErr *PatchError
+			if errors.As(err, &pErr) {
+				return err
+			}
+
+			return &PatchError{Op: PatchOpMerge, Path: fieldPointer, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// jsonPatchOperation is an operation of RFC 6902 JSON Patch
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// apply() translates the operation of RFC 6902 JSON Patch
+func (u *patchUpdate) apply(t reflect.Type, op jsonPatchOperation) error {
+	target, err := resolvePatchPath(t, op.Path)
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case PatchOpAdd, PatchOpReplace, PatchOpTest:
+		if op.Value == nil {
+			return errPatchMissingValue
+		}
+
+		if target.arrayElement && target.last == "-" && op.Op != PatchOpAdd {
+			return errPatchInvalidKey
+		}
+
+		v, err := decodePatchValue(op.Value, target.typ)
+		if err != nil {
+			return err
+		}
+
+		if op.Op == PatchOpTest {
+			u.test = append(u.test, primitive.E{Key: target.path, Value: v})
+			return nil
+		}
+
+		if op.Op == PatchOpAdd && target.arrayElement {
+			return u.insert(target, v)
+		}
+
+		if op.Op == PatchOpReplace {
+			u.mustExist(target.path)
+		}
+
+		return u.add(&u.set, target.path, v)
+	case PatchOpRemove:
+		if target.arrayElement {
+			return errPatchArrayElement
+		}
+
+		u.mustExist(target.path)
+
+		return u.add(&u.unset, target.path, "")
+	case PatchOpMove:
+		from, err := resolvePatchPath(t, op.From)
+		if err != nil {
+			return err
+		}
+
+		if from.arrayElement || target.arrayElement {
+			return errPatchArrayElement
+		}
+
+		if from.typ != target.typ {
+			return errPatchTypeMismatch
+		}
+
+		if err := u.add(&u.rename, from.path, target.path); err != nil {
+			return err
+		}
+
+		u.mustExist(from.path)
+
+		if err := u.conflict(target.path); err != nil {
+			return err
+		}
+
+		u.paths = append(u.paths, target.path)
+
+		return nil
+	default:
+		return errPatchUnsupported
+	}
+}
+
+// mustExist() adds a test condition of the field existence, RFC 6902 requires
+// the target of replace and remove and the source of move to exist
+func (u *patchUpdate) mustExist(path string) {
+	u.test = append(u.test, primitive.E{Key: path, Value: bson.M{"$exists": true}})
+}
+
+// insert() inserts the value to the array, "-" appends it to the end
+func (u *patchUpdate) insert(target patchPath, v interface{}) error {
+	arrayPath := strings.TrimSuffix(target.path, "."+target.last)
+
+	if target.last == "-" {
+		return u.add(&u.push, arrayPath, v)
+	}
+
+	pos, err := strconv.Atoi(target.last)
+	if err != nil || pos < 0 {
+		return errPatchInvalidKey
+	}
+
+	return u.add(&u.push, arrayPath, bson.D{
+		{Key: "$each", Value: bson.A{v}},
+		{Key: "$position", Value: pos},
+	})
+}
+
+// patchPath is a JSON pointer resolved against the model
+type patchPath struct {
+	// path is a dotted BSON path
+	path string
+	// typ is the type of the value at the path
+	typ reflect.Type
+	// last is the last segment of the JSON pointer
+	last string
+	// arrayElement is true for paths of array elements
+	arrayElement bool
+}
+
+// resolvePatchPath() translates RFC 6901 JSON pointer to the dotted BSON path of the model
+func resolvePatchPath(t reflect.Type, pointer string) (patchPath, error) {
+	if pointer == "" || pointer[0] != '/' {
+		return patchPath{}, errPatchInvalidKey
+	}
+
+	segments := strings.Split(pointer[1:], "/")
+	keys := make([]string, len(segments))
+	target := patchPath{typ: t}
+
+	for i, seg := range segments {
+		seg = strings.ReplaceAll(strings.ReplaceAll(seg, "~1", "/"), "~0", "~")
+
+		field, err := patchStep(target.typ, seg)
+		if err != nil {
+			return patchPath{}, err
+		}
+
+		if i == 0 && field.key == CollectionIDKey {
+			return patchPath{}, errPatchIDField
+		}
+
+		if seg == "-" && i != len(segments)-1 {
+			return patchPath{}, errPatchInvalidKey
+		}
+
+		keys[i] = field.key
+		target.typ = field.typ
+		target.last = field.key
+		target.arrayElement = field.arrayElement
+	}
+
+	target.path = strings.Join(keys, ".")
+
+	return target, nil
+}
+
+// patchField is a field of the model resolved by a JSON key
+type patchField struct {
+	key          string
+	typ          reflect.Type
+	arrayElement bool
+}
+
+// patchStep() resolves the JSON key of the struct field, map key or array index
+func patchStep(t reflect.Type, key string) (patchField, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if key == "" || strings.Contains(key, ".") || strings.HasPrefix(key, "$") {
+		return patchField{}, errPatchInvalidKey
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		f, ok := jsonToBSONFields(t)[key]
+		if !ok {
+			return patchField{}, ErrUnknownField
+		}
+
+		return f, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return patchField{}, errPatchInvalidKey
+		}
+
+		return patchField{key: key, typ: t.Elem()}, nil
+	case reflect.Slice, reflect.Array:
+		if key != "-" {
+			if i, err := strconv.Atoi(key); err != nil || i < 0 {
+				return patchField{}, errPatchInvalidKey
+			}
+		}
+
+		return patchField{key: key, typ: t.Elem(), arrayElement: true}, nil
+	case reflect.Interface:
+		return patchField{key: key, typ: t}, nil
+	default:
+		return patchField{}, errPatchScalarParent
+	}
+}
+
+// jsonToBSONFields() maps JSON names of the struct fields to BSON keys,
+// fields of embedded structs are promoted like encoding/json does
+func jsonToBSONFields(t reflect.Type) map[string]patchField {
+	fields := make(map[string]patchField)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+		bsonName, inline, skip := bsonFieldKey(f)
+
+		if skip || jsonName == "-" {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && jsonName == "" && ft.Kind() == reflect.Struct {
+			prefix := bsonName + "."
+			if inline {
+				prefix = ""
+			}
+
+			for name, nested := range jsonToBSONFields(ft) {
+				if _, ok := fields[name]; !ok {
+					fields[name] = patchField{key: prefix + nested.key, typ: nested.typ}
+				}
+			}
+
+			continue
+		}
+
+		if jsonName == "" {
+			jsonName = f.Name
+		}
+
+		fields[jsonName] = patchField{key: bsonName, typ: f.Type}
+	}
+
+	return fields
+}
+
+// isPatchContainer() returns true if JSON objects are merged into the values of the type field by field
+func isPatchContainer(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return !reflect.PtrTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem())
+	case reflect.Map, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodePatchValue() decodes JSON value into the type of the model field
+func decodePatchValue(raw json.RawMessage, t reflect.Type) (interface{}, error) {
+	v := reflect.New(t)
+	if err := json.Unmarshal(raw, v.Interface()); err != nil {
+		return nil, err
+	}
+
+	return v.Elem().Interface(), nil
+}
@@ -0,0 +1,225 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/wajox/mongol"
+)
+
+type PatchAddress struct {
+	City   string `json:"city" bson:"city,omitempty"`
+	Street string `json:"street" bson:"street,omitempty"`
+	Zip    string `json:"zip" bson:"zip,omitempty"`
+}
+
+type PatchModel struct {
+	BaseDocument `bson:",inline"`
+
+	Title   string                 `json:"title" bson:"title,omitempty"`
+	Count   int                    `json:"count" bson:"cnt,omitempty"`
+	Tags    []string               `json:"tags" bson:"tags,omitempty"`
+	Address *PatchAddress          `json:"address" bson:"addr,omitempty"`
+	Meta    map[string]interface{} `json:"meta" bson:"meta,omitempty"`
+	Secret  string                 `json:"-" bson:"secret,omitempty"`
+}
+
+// nolint
+var _ = Describe("Patch", func() {
+	Describe("ParsePatch()", func() {
+		Context("with JSON Merge Patch", func() {
+			It("should translate JSON fields to BSON fields", func() {
+				update, test, err := ParsePatch([]byte(`{
+					"title": "new title",
+					"count": 2,
+					"address": {"city": "Berlin", "zip": null},
+					"meta": {"source": "api"}
+				}`), &PatchModel{})
+
+				Expect(err).To(BeNil())
+				Expect(test).To(BeEmpty())
+				Expect(update).To(Equal(bson.D{
+					{Key: "$set", Value: bson.D{
+						{Key: "addr.city", Value: "Berlin"},
+						{Key: "cnt", Value: 2},
+						{Key: "meta.source", Value: "api"},
+						{Key: "title", Value: "new title"},
+					}},
+					{Key: "$unset", Value: bson.D{
+						{Key: "addr.zip", Value: ""},
+					}},
+				}))
+			})
+
+			It("should replace arrays as a whole", func() {
+				update, _, err := ParsePatch([]byte(`{"tags": ["a", "b"]}`), &PatchModel{})
+
+				Expect(err).To(BeNil())
+				Expect(update).To(Equal(bson.D{
+					{Key: "$set", Value: bson.D{{Key: "tags", Value: []string{"a", "b"}}}},
+				}))
+			})
+
+			It("should reject invalid patches", func() {
+				for path, patch := range map[string]string{
+					"/unknown":     `{"unknown": 1}`,
+					"/secret":      `{"secret": "value"}`,
+					"/count":       `{"count": "two"}`,
+					"/id":          `{"id": "5f2b7e5c0000000000000000"}`,
+					"/meta/$where": `{"meta": {"$where": "sleep(1000)"}}`,
+					"/title":       `{"title": {"x": 1}}`,
+				} {
+					_, _, err := ParsePatch([]byte(patch), &PatchModel{})
+					Expect(errors.Is(err, ErrInvalidPatch)).To(BeTrue(), patch)
+
+					var pErr *PatchError
+					Expect(errors.As(err, &pErr)).To(BeTrue())
+					Expect(pErr.Op).To(Equal(PatchOpMerge))
+					Expect(pErr.Path).To(Equal(path))
+				}
+			})
+		})
+
+		Context("with JSON Patch", func() {
+			It("should translate operations to update operators", func() {
+				update, test, err := ParsePatch([]byte(`[
+					{"op": "test", "path": "/title", "value": "title"},
+					{"op": "replace", "path": "/title", "value": "new title"},
+					{"op": "add", "path": "/tags/-", "value": "go"},
+					{"op": "remove", "path": "/address/zip"},
+					{"op": "move", "from": "/address/city", "path": "/address/street"}
+				]`), &PatchModel{})
+
+				Expect(err).To(BeNil())
+				Expect(test).To(Equal(bson.D{
+					{Key: "title", Value: "title"},
+					{Key: "title", Value: bson.M{"$exists": true}},
+					{Key: "addr.zip", Value: bson.M{"$exists": true}},
+					{Key: "addr.city", Value: bson.M{"$exists": true}},
+				}))
+				Expect(update).To(Equal(bson.D{
+					{Key: "$set", Value: bson.D{{Key: "title", Value: "new title"}}},
+					{Key: "$unset", Value: bson.D{{Key: "addr.zip", Value: ""}}},
+					{Key: "$push", Value: bson.D{{Key: "tags", Value: "go"}}},
+					{Key: "$rename", Value: bson.D{{Key: "addr.city", Value: "addr.street"}}},
+				}))
+			})
+
+			It("should insert array elements at the position", func() {
+				update, _, err := ParsePatch([]byte(`[{"op": "add", "path": "/tags/1", "value": "go"}]`), &PatchModel{})
+
+				Expect(err).To(BeNil())
+				Expect(update).To(Equal(bson.D{
+					{Key: "$push", Value: bson.D{{Key: "tags", Value: bson.D{
+						{Key: "$each", Value: bson.A{"go"}},
+						{Key: "$position", Value: 1},
+					}}}},
+				}))
+			})
+
+			It("should reject unsupported operations", func() {
+				for _, patch := range []string{
+					`[{"op": "remove", "path": "/tags/0"}]`,
+					`[{"op": "copy", "from": "/title", "path": "/address/city"}]`,
+					`[{"op": "replace", "path": "/tags/-", "value": "go"}]`,
+					`[{"op": "replace", "path": "/title"}]`,
+					`[{"op": "move", "from": "/title", "path": "/count"}]`,
+					`[{"op": "replace", "path": "/address", "value": {}}, {"op": "remove", "path": "/address/city"}]`,
+					`[{"op": "replace", "path": "", "value": {}}]`,
+					`"title"`,
+				} {
+					_, _, err := ParsePatch([]byte(patch), &PatchModel{})
+					Expect(errors.Is(err, ErrInvalidPatch)).To(BeTrue(), patch)
+				}
+			})
+		})
+	})
+
+	Describe(".PatchOneByID()", func() {
+		var (
+			storage *BaseCollection
+		)
+
+		BeforeEach(func() {
+			storage = newTestCollection("patch_test")
+		})
+
+		It("should wrap invalid patches", func() {
+			err := storage.PatchOneByID(context.TODO(), "5f2b7e5c0000000000000000", []byte(`{"unknown": 1}`), &PatchModel{})
+
+			var opErr *OperationError
+			Expect(errors.As(err, &opErr)).To(BeTrue())
+			Expect(opErr.Method).To(Equal(PatchOneByIDMethod))
+			Expect(errors.Is(err, ErrInvalidPatch)).To(BeTrue())
+		})
+
+		Context("with existing document", func() {
+			var (
+				id string
+			)
+
+			BeforeEach(func() {
+				var err error
+				id, err = storage.InsertOne(context.TODO(), &PatchModel{
+					Title:   "title",
+					Count:   1,
+					Address: &PatchAddress{City: "Berlin", Zip: "10115"},
+				})
+				Expect(err).To(BeNil())
+			})
+
+			AfterEach(func() {
+				storage.DeleteAll(context.TODO())
+			})
+
+			It("should apply JSON Merge Patch", func() {
+				m := &PatchModel{}
+				err := storage.PatchOneByID(context.TODO(), id, []byte(`{"title": "new title", "address": {"zip": null}}`), m)
+
+				Expect(err).To(BeNil())
+				Expect(m.GetHexID()).To(Equal(id))
+				Expect(m.Title).To(Equal("new title"))
+				Expect(m.Count).To(Equal(1))
+				Expect(m.Address).To(Equal(&PatchAddress{City: "Berlin"}))
+				Expect(m.UpdatedAt).NotTo(BeZero())
+			})
+
+			It("should apply JSON Patch", func() {
+				m := &PatchModel{}
+				err := storage.PatchOneByID(context.TODO(), id, []byte(`[
+					{"op": "test", "path": "/count", "value": 1},
+					{"op": "add", "path": "/tags/-", "value": "go"},
+					{"op": "replace", "path": "/count", "value": 2}
+				]`), m)
+
+				Expect(err).To(BeNil())
+				Expect(m.Tags).To(Equal([]string{"go"}))
+				Expect(m.Count).To(Equal(2))
+			})
+
+			It("should return ErrPatchTestFailed", func() {
+				err := storage.PatchOneByID(context.TODO(), id, []byte(`[
+					{"op": "test", "path": "/count", "value": 5},
+					{"op": "replace", "path": "/count", "value": 2}
+				]`), &PatchModel{})
+
+				Expect(errors.Is(err, ErrPatchTestFailed)).To(BeTrue())
+			})
+
+			It("should not replace or remove missing fields", func() {
+				for _, patch := range []string{
+					`[{"op": "replace", "path": "/tags", "value": ["go"]}]`,
+					`[{"op": "remove", "path": "/address/street"}]`,
+					`[{"op": "move", "from": "/address/street", "path": "/title"}]`,
+				} {
+					err := storage.PatchOneByID(context.TODO(), id, []byte(patch), &PatchModel{})
+					Expect(errors.Is(err, ErrPatchTestFailed)).To(BeTrue(), patch)
+				}
+			})
+		})
+	})
+})
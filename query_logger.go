@@ -0,0 +1,237 @@
+package mongol
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultSlowQueryThreshold is a duration of operations logged as slow by QueryLogger
+	DefaultSlowQueryThreshold = time.Millisecond * 100
+	// RedactedValue replaces values of redacted fields
+	RedactedValue = "[REDACTED]"
+)
+
+// Logger is a structured logger used by QueryLogger, *slog.Logger implements it
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+var (
+	_ Instrumentation = (*QueryLogger)(nil)
+)
+
+// QueryLogger is Instrumentation which logs every operation,
+// operations are logged at debug level, slow ones at warn level and failed ones at error level
+type QueryLogger struct {
+	Logger Logger
+	// SlowThreshold is a duration of slow operations, zero disables slow query detection
+	SlowThreshold time.Duration
+	// RedactedFields are replaced by RedactedValue in filters and documents,
+	// a field matches by its name or dotted path, e.g. "password" or "card.number"
+	RedactedFields []string
+}
+
+// NewQueryLogger() is a constructor for QueryLogger struct
+func NewQueryLogger(logger Logger, redactedFields ...string) *QueryLogger {
+	return &QueryLogger{
+		Logger:         logger,
+		SlowThreshold:  DefaultSlowQueryThreshold,
+		RedactedFields: redactedFields,
+	}
+}
+
+// StartOperation() does nothing, operations are logged when they are finished
+func (l *QueryLogger) StartOperation(ctx context.Context, op *Operation) context.Context {
+	return ctx
+}
+
+// FinishOperation() logs the operation
+func (l *QueryLogger) FinishOperation(ctx context.Context, op *Operation) {
+	args := l.args(op)
+
+	switch {
+	case op.Err != nil && op.ErrorClass() != ErrorClassNotFound:
+		l.Logger.ErrorContext(ctx, "mongol query failed", args...)
+	case l.SlowThreshold > 0 && op.Duration >= l.SlowThreshold:
+		l.Logger.WarnContext(ctx, "mongol slow query", args...)
+	default:
+		l.Logger.DebugContext(ctx, "mongol query", args...)
+	}
+}
+
+func (l *QueryLogger) args(op *Operation) []interface{} {
+	args := []interface{}{
+		"db", op.DBName,
+		"collection", op.Collection,
+		"method", op.Method,
+		"duration", op.Duration,
+	}
+
+	if op.Filter != nil {
+		args = append(args, "filter", l.redact(op.Filter))
+	}
+
+	if b, ok := op.Document.(*BulkWriteBuilder); ok {
+		args = append(args, "operations", b.Len())
+	} else if op.Document != nil {
+		args = append(args, "document", l.redact(op.Document))
+	}
+
+	if opts := describeOptions(op.Options); len(opts) > 0 {
+		args = append(args, "options", opts)
+	}
+
+	args = append(args,
+		"documents", op.Documents,
+		"matched", op.Matched,
+		"modified", op.Modified,
+		"deleted", op.Deleted,
+		"retries", op.Retries,
+	)
+
+	if op.Err != nil {
+		args = append(args, "error", op.Err)
+	}
+
+	return args
+}
+
+// redact() returns a BSON representation of v with redacted fields
+func (l *QueryLogger) redact(v interface{}) interface{} {
+	fields := make(map[string]bool, len(l.RedactedFields))
+	for _, f := range l.RedactedFields {
+		fields[f] = true
+	}
+
+	return redactValue(v, "", fields)
+}
+
+func redactValue(v interface{}, path string, fields map[string]bool) interface{} {
+	if fb, ok := v.(*FilterBuilder); ok {
+		v = fb.GetQuery()
+	}
+
+	switch val := v.(type) {
+	case bson.M:
+		return redactMap(val, path, fields)
+	case map[string]interface{}:
+		return redactMap(val, path, fields)
+	case bson.D:
+		d := make(bson.D, len(val))
+		for i := range val {
+			d[i] = primitive.E{Key: val[i].Key, Value: redactField(val[i].Key, val[i].Value, path, fields)}
+		}
+
+		return d
+	case bson.A:
+		return redactSlice(val, path, fields)
+	case []interface{}:
+		return redactSlice(val, path, fields)
+	}
+
+	// documents are logged in their BSON form
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Struct || (rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct) {
+		b, err := bson.Marshal(v)
+		if err != nil {
+			return v
+		}
+
+		d := bson.D{}
+		if err := bson.Unmarshal(b, &d); err != nil {
+			return v
+		}
+
+		return redactValue(d, path, fields)
+	}
+
+	return v
+}
+
+func redactField(key string, v interface{}, path string, fields map[string]bool) interface{} {
+	// operators like $set or $and do not change the path of fields
+	if strings.HasPrefix(key, "$") {
+		return redactValue(v, path, fields)
+	}
+
+	if path != "" {
+		key = path + "." + key
+	}
+
+	if fields[key] || fields[key[strings.LastIndex(key, ".")+1:]] {
+		return RedactedValue
+	}
+
+	return redactValue(v, key, fields)
+}
+
+func redactMap(m map[string]interface{}, path string, fields map[string]bool) bson.M {
+	redacted := make(bson.M, len(m))
+	for k, v := range m {
+		redacted[k] = redactField(k, v, path, fields)
+	}
+
+	return redacted
+}
+
+func redactSlice(a []interface{}, path string, fields map[string]bool) bson.A {
+	redacted := make(bson.A, len(a))
+	for i := range a {
+		redacted[i] = redactValue(a[i], path, fields)
+	}
+
+	return redacted
+}
+
+// describeOptions() returns set fields of driver options, e.g. []*options.FindOptions
+func describeOptions(opts interface{}) map[string]interface{} {
+	described := make(map[string]interface{})
+
+	rv := reflect.ValueOf(opts)
+	if rv.Kind() == reflect.Ptr {
+		rv = reflect.ValueOf([]interface{}{opts})
+	}
+
+	if rv.Kind() != reflect.Slice {
+		return described
+	}
+
+	for i := 0; i < rv.Len(); i++ {
+		o := rv.Index(i)
+		for o.Kind() == reflect.Ptr || o.Kind() == reflect.Interface {
+			if o.IsNil() {
+				break
+			}
+
+			o = o.Elem()
+		}
+
+		if o.Kind() != reflect.Struct {
+			continue
+		}
+
+		for j := 0; j < o.NumField(); j++ {
+			f := o.Field(j)
+			// unexported fields have PkgPath
+			if o.Type().Field(j).PkgPath != "" || f.IsZero() {
+				continue
+			}
+
+			if f.Kind() == reflect.Ptr {
+				f = f.Elem()
+			}
+
+			described[o.Type().Field(j).Name] = f.Interface()
+		}
+	}
+
+	return described
+}
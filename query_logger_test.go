@@ -0,0 +1,126 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/wajox/mongol"
+)
+
+type logRecord struct {
+	level string
+	msg   string
+	attrs map[string]interface{}
+}
+
+type recordingLogger struct {
+	records []logRecord
+}
+
+func (l *recordingLogger) log(level, msg string, args []interface{}) {
+	attrs := make(map[string]interface{})
+	for i := 0; i+1 < len(args); i += 2 {
+		attrs[args[i].(string)] = args[i+1]
+	}
+
+	l.records = append(l.records, logRecord{level: level, msg: msg, attrs: attrs})
+}
+
+func (l *recordingLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("debug", msg, args)
+}
+
+func (l *recordingLogger) WarnContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("warn", msg, args)
+}
+
+func (l *recordingLogger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("error", msg, args)
+}
+
+var _ = Describe("QueryLogger", func() {
+	var (
+		logger *recordingLogger
+		ql     *QueryLogger
+		op     *Operation
+	)
+
+	BeforeEach(func() {
+		logger = &recordingLogger{}
+		ql = NewQueryLogger(logger, "password", "card.number")
+
+		op = &Operation{
+			DBName:     "db",
+			Collection: "users",
+			Method:     FindAllByFilterMethod,
+			Filter:     bson.M{"email": "john@example.com", "password": "secret"},
+			Options:    []*options.FindOptions{options.Find().SetLimit(10)},
+			Duration:   time.Millisecond,
+			Documents:  3,
+		}
+	})
+
+	It("should log operations at debug level", func() {
+		ql.FinishOperation(context.TODO(), op)
+
+		Expect(logger.records).To(HaveLen(1))
+
+		r := logger.records[0]
+		Expect(r.level).To(Equal("debug"))
+		Expect(r.attrs["method"]).To(Equal(FindAllByFilterMethod))
+		Expect(r.attrs["collection"]).To(Equal("users"))
+		Expect(r.attrs["documents"]).To(Equal(int64(3)))
+		Expect(r.attrs["options"]).To(Equal(map[string]interface{}{"Limit": int64(10)}))
+		Expect(r.attrs["filter"]).To(Equal(bson.M{"email": "john@example.com", "password": RedactedValue}))
+	})
+
+	It("should log slow operations at warn level", func() {
+		op.Duration = DefaultSlowQueryThreshold
+		ql.FinishOperation(context.TODO(), op)
+
+		Expect(logger.records[0].level).To(Equal("warn"))
+
+		ql.SlowThreshold = 0
+		ql.FinishOperation(context.TODO(), op)
+
+		Expect(logger.records[1].level).To(Equal("debug"))
+	})
+
+	It("should log failed operations at error level", func() {
+		op.Err = ErrDocumentNotFound
+		ql.FinishOperation(context.TODO(), op)
+
+		op.Err = errors.New("connection refused")
+		ql.FinishOperation(context.TODO(), op)
+
+		Expect(logger.records[0].level).To(Equal("debug"))
+		Expect(logger.records[1].level).To(Equal("error"))
+		Expect(logger.records[1].attrs["error"]).To(Equal(op.Err))
+	})
+
+	It("should redact documents and updates", func() {
+		op.Document = bson.M{"$set": bson.M{
+			"card":     bson.M{"number": "4242", "holder": "John"},
+			"password": "secret",
+		}}
+		ql.FinishOperation(context.TODO(), op)
+
+		Expect(logger.records[0].attrs["document"]).To(Equal(bson.M{"$set": bson.M{
+			"card":     bson.M{"number": RedactedValue, "holder": "John"},
+			"password": RedactedValue,
+		}}))
+
+		op.Document = &struct {
+			Password string `bson:"password"`
+		}{Password: "secret"}
+		ql.FinishOperation(context.TODO(), op)
+
+		Expect(logger.records[1].attrs["document"]).To(Equal(bson.D{{Key: "password", Value: RedactedValue}}))
+	})
+})
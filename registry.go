@@ -0,0 +1,260 @@
+package mongol
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CollectionDefinition describes a collection of the Registry
+type CollectionDefinition struct {
+	// Name of the collection
+	Name string
+	// Model is a prototype of the collection documents, the collection can be accessed by its type
+	Model Document
+	// Indexes are created by Registry.Init()
+	Indexes []mongo.IndexModel
+	// Validator, e.g. bson.M{"$jsonSchema": ...}, is applied by Registry.Init()
+	Validator interface{}
+	// Setup is called once the collection is created, it can add hooks or set IDStrategy
+	Setup func(s *BaseCollection)
+}
+
+// CollectionHealth
+type CollectionHealth struct {
+	Exists    bool
+	Documents int64
+	Err       error
+}
+
+// RegistryHealth is a health report of the Registry
+type RegistryHealth struct {
+	// Err of the server ping
+	Err         error
+	Collections map[string]CollectionHealth
+}
+
+// Healthy() returns true if the server and all collections are available
+func (h *RegistryHealth) Healthy() bool {
+	if h.Err != nil {
+		return false
+	}
+
+	for _, c := range h.Collections {
+		if c.Err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Registry keeps collections of one database, collections are created lazily on the first access
+type Registry struct {
+	Client *Client
+	DBName string
+
+	mu          sync.Mutex
+	names       []string
+	definitions map[string]CollectionDefinition
+	types       map[reflect.Type]string
+	collections map[string]*BaseCollection
+}
+
+// NewRegistry() is a constructor for Registry struct
+func NewRegistry(client *Client, dbName string) *Registry {
+	return &Registry{
+		Client:      client,
+		DBName:      dbName,
+		definitions: make(map[string]CollectionDefinition),
+		types:       make(map[reflect.Type]string),
+		collections: make(map[string]*BaseCollection),
+	}
+}
+
+// Register() adds the collection definition, names and model types have to be unique
+func (r *Registry) Register(def CollectionDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.definitions[def.Name]; ok {
+		return ErrCollectionAlreadyRegistered
+	}
+
+	if def.Model != nil {
+		t := modelType(def.Model)
+		if _, ok := r.types[t]; ok {
+			return ErrCollectionAlreadyRegistered
+		}
+
+		r.types[t] = def.Name
+	}
+
+	r.names = append(r.names, def.Name)
+	r.definitions[def.Name] = def
+
+	return nil
+}
+
+// MustRegister() adds the collection definition and panics on error
+func (r *Registry) MustRegister(def CollectionDefinition) *Registry {
+	if err := r.Register(def); err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Collection() returns the registered collection by its name
+func (r *Registry) Collection(name string) (*BaseCollection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.collection(name)
+}
+
+// CollectionFor() returns the collection registered for the type of the model
+func (r *Registry) CollectionFor(m Document) (*BaseCollection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name, ok := r.types[modelType(m)]
+	if !ok {
+		return nil, ErrCollectionNotRegistered
+	}
+
+	return r.collection(name)
+}
+
+// Names() returns names of the registered collections in order of registration
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, len(r.names))
+	copy(names, r.names)
+
+	return names
+}
+
+func (r *Registry) collection(name string) (*BaseCollection, error) {
+	if s, ok := r.collections[name]; ok {
+		return s, nil
+	}
+
+	def, ok := r.definitions[name]
+	if !ok {
+		return nil, ErrCollectionNotRegistered
+	}
+
+	s := NewBaseCollectionWithClient(r.Client, r.DBName, def.Name)
+	if def.Setup != nil {
+		def.Setup(s)
+	}
+
+	r.collections[name] = s
+
+	return s, nil
+}
+
+// Init() applies validators and creates indexes of all registered collections
+func (r *Registry) Init(ctx context.Context) error {
+	db := r.Client.MongoClient().Database(r.DBName)
+
+	existing, err := db.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+
+	exists := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		exists[name] = true
+	}
+
+	for _, name := range r.Names() {
+		r.mu.Lock()
+		def := r.definitions[name]
+		r.mu.Unlock()
+
+		if err := syncValidator(ctx, db, def, exists[name]); err != nil {
+			return err
+		}
+
+		if len(def.Indexes) == 0 {
+			continue
+		}
+
+		if _, err := db.Collection(name).Indexes().CreateMany(ctx, def.Indexes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Health() pings the server and checks all registered collections
+func (r *Registry) Health(ctx context.Context) *RegistryHealth {
+	h := &RegistryHealth{Collections: make(map[string]CollectionHealth)}
+
+	if h.Err = r.Client.MongoClient().Ping(ctx, nil); h.Err != nil {
+		return h
+	}
+
+	db := r.Client.MongoClient().Database(r.DBName)
+
+	existing, err := db.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		h.Err = err
+		return h
+	}
+
+	exists := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		exists[name] = true
+	}
+
+	for _, name := range r.Names() {
+		count, err := db.Collection(name).EstimatedDocumentCount(ctx)
+
+		h.Collections[name] = CollectionHealth{
+			Exists:    exists[name],
+			Documents: count,
+			Err:       err,
+		}
+	}
+
+	return h
+}
+
+// Close() releases the client of the registry
+func (r *Registry) Close(ctx context.Context) error {
+	return r.Client.Close(ctx)
+}
+
+func syncValidator(ctx context.Context, db *mongo.Database, def CollectionDefinition, exists bool) error {
+	if def.Validator == nil {
+		return nil
+	}
+
+	if !exists {
+		return db.CreateCollection(ctx, def.Name, options.CreateCollection().SetValidator(def.Validator))
+	}
+
+	return db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: def.Name},
+		{Key: "validator", Value: def.Validator},
+	}).Err()
+}
+
+func modelType(m Document) reflect.Type {
+	t := reflect.TypeOf(m)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
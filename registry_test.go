@@ -0,0 +1,112 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("Registry", func() {
+	var (
+		client   *Client
+		registry *Registry
+	)
+
+	BeforeEach(func() {
+		var err error
+		client, err = NewClient(context.TODO(), testMongoURI())
+		Expect(err).To(BeNil())
+
+		registry = NewRegistry(client, "registry_db_test")
+	})
+
+	Describe("Register()", func() {
+		It("should register collections with unique names and models", func() {
+			Expect(registry.Register(CollectionDefinition{Name: "examples", Model: &ExampleModel{}})).To(BeNil())
+			Expect(registry.Register(CollectionDefinition{Name: "uuid_examples", Model: &UUIDModel{}})).To(BeNil())
+
+			Expect(registry.Register(CollectionDefinition{Name: "examples"})).To(Equal(ErrCollectionAlreadyRegistered))
+			Expect(registry.Register(CollectionDefinition{Name: "other", Model: &ExampleModel{}})).To(Equal(ErrCollectionAlreadyRegistered))
+			Expect(registry.Names()).To(Equal([]string{"examples", "uuid_examples"}))
+		})
+	})
+
+	Describe("Collection()", func() {
+		BeforeEach(func() {
+			registry.MustRegister(CollectionDefinition{
+				Name:  "examples",
+				Model: &ExampleModel{},
+				Setup: func(s *BaseCollection) {
+					s.SetIDStrategy(StringIDStrategy{})
+				},
+			})
+		})
+
+		It("should create the collection lazily once", func() {
+			s1, err := registry.Collection("examples")
+			Expect(err).To(BeNil())
+			Expect(s1.CollectionName).To(Equal("examples"))
+			Expect(s1.DBName).To(Equal("registry_db_test"))
+			Expect(s1.IDStrategy).To(Equal(StringIDStrategy{}))
+
+			s2, err := registry.CollectionFor(&ExampleModel{})
+			Expect(err).To(BeNil())
+			Expect(s2).To(BeIdenticalTo(s1))
+		})
+
+		It("should not return unknown collections", func() {
+			_, err := registry.Collection("unknown")
+			Expect(err).To(Equal(ErrCollectionNotRegistered))
+
+			_, err = registry.CollectionFor(&UUIDModel{})
+			Expect(err).To(Equal(ErrCollectionNotRegistered))
+		})
+	})
+
+	// nolint
+	Describe("Init()", func() {
+		AfterEach(func() {
+			client.MongoClient().Database("registry_db_test").Drop(context.TODO())
+		})
+
+		It("should create indexes, validators and report health", func() {
+			registry.MustRegister(CollectionDefinition{
+				Name:  "examples",
+				Model: &ExampleModel{},
+				Indexes: []mongo.IndexModel{
+					{Keys: bson.D{{Key: "title", Value: 1}}, Options: options.Index().SetUnique(true)},
+				},
+				Validator: bson.M{"$jsonSchema": bson.M{
+					"bsonType": "object",
+					"required": bson.A{"title"},
+				}},
+			})
+
+			Expect(registry.Init(context.TODO())).To(BeNil())
+			Expect(registry.Init(context.TODO())).To(BeNil())
+
+			s, _ := registry.Collection("examples")
+
+			_, err := s.InsertOne(context.TODO(), &ExampleModel{})
+			Expect(err).NotTo(BeNil())
+
+			_, err = s.InsertOne(context.TODO(), &ExampleModel{Title: "title"})
+			Expect(err).To(BeNil())
+
+			_, err = s.InsertOne(context.TODO(), &ExampleModel{Title: "title"})
+			Expect(errors.Is(err, ErrDocumentDuplication)).To(BeTrue())
+
+			h := registry.Health(context.TODO())
+			Expect(h.Healthy()).To(BeTrue())
+			Expect(h.Collections["examples"].Exists).To(BeTrue())
+			Expect(h.Collections["examples"].Documents).To(Equal(int64(1)))
+		})
+	})
+})
@@ -0,0 +1,429 @@
+package mongol
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// PopulateStrategy defines how related documents are loaded
+type PopulateStrategy int
+
+const (
+	// PopulateBatch loads related documents of all found documents by one $in query per relation
+	PopulateBatch PopulateStrategy = iota
+	// PopulateLookup loads related documents of all relations by one $lookup aggregation
+	PopulateLookup
+)
+
+// Relation describes a reference to the documents of another collection of the database
+//
+//	// one-to-one: post.author_id -> users._id
+//	Relation{Collection: "users", LocalField: "author_id", As: "author"}
+//	// one-to-many by array of IDs: post.tag_ids -> tags._id
+//	Relation{Collection: "tags", LocalField: "tag_ids", As: "tags", Many: true}
+//	// one-to-many by ID: post._id <- comments.post_id
+//	Relation{Collection: "comments", LocalField: "_id", ForeignField: "post_id", As: "comments", Many: true}
+type Relation struct {
+	// Collection with the related documents, the name of Target is used if empty
+	Collection string
+	// Target is the collection of the related documents, e.g. one of the Registry, its default scopes,
+	// hooks and tenancy are applied. A collection without scopes sharing the client and settings
+	// of the collection is used if nil
+	Target *BaseCollection
+	// LocalField is a key of the document with an ID or an array of IDs
+	LocalField string
+	// ForeignField is a key of the related documents, CollectionIDKey is used if empty
+	ForeignField string
+	// As is a key of the document field receiving related documents, the relation name is used if empty
+	// The field is not stored by UpdateOne(), UpdateManyByFilter(), ReplaceOne() and Save(),
+	// use omitempty for it, so InsertOne() does not store it either
+	As string
+	// Many decodes all related documents into a slice, otherwise the first one is decoded
+	Many     bool
+	Strategy PopulateStrategy
+}
+
+type populateCtxKey struct{}
+
+// WithPopulate() returns a context which makes GetOneByID(), GetOneByFilter() and GetManyByFilter()
+// load documents of the named relations
+func WithPopulate(ctx context.Context, relations ...string) context.Context {
+	names := append(PopulateFromContext(ctx), relations...)
+
+	return context.WithValue(ctx, populateCtxKey{}, names)
+}
+
+// PopulateFromContext() returns names of the relations to populate
+func PopulateFromContext(ctx context.Context) []string {
+	names, _ := ctx.Value(populateCtxKey{}).([]string)
+
+	return append([]string(nil), names...)
+}
+
+// AddRelation() registers a named relation, see WithPopulate()
+func (s *BaseCollection) AddRelation(name string, r Relation) {
+	if s.Relations == nil {
+		s.Relations = make(map[string]Relation)
+	}
+
+	if r.Collection == "" && r.Target != nil {
+		r.Collection = r.Target.CollectionName
+	}
+
+	if r.ForeignField == "" {
+		r.ForeignField = CollectionIDKey
+	}
+
+	if r.As == "" {
+		r.As = name
+	}
+
+	s.Relations[name] = r
+}
+
+// RelationNames() returns sorted names of the registered relations
+func (s *BaseCollection) RelationNames() []string {
+	names := make([]string, 0, len(s.Relations))
+	for name := range s.Relations {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// populate() loads related documents of the context relations and decodes them into docs,
+// raws are BSON of docs
+func (s *BaseCollection) populate(ctx context.Context, raws []bson.Raw, docs []interface{}) error {
+	names := PopulateFromContext(ctx)
+	if len(names) == 0 || len(docs) == 0 {
+		return nil
+	}
+
+	var lookups []Relation
+
+	// values[i] are fields of related documents of docs[i]
+	values := make([]bson.D, len(docs))
+
+	for _, name := range names {
+		r, ok := s.Relations[name]
+		if !ok {
+			return ErrRelationNotRegistered
+		}
+
+		if r.Strategy == PopulateLookup {
+			lookups = append(lookups, r)
+			continue
+		}
+
+		if err := s.populateBatch(ctx, r, raws, values); err != nil {
+			return err
+		}
+	}
+
+	if len(lookups) > 0 {
+		if err := s.populateLookup(ctx, lookups, raws, values); err != nil {
+			return err
+		}
+	}
+
+	for i := range docs {
+		if len(values[i]) == 0 {
+			continue
+		}
+
+		b, err := bson.MarshalWithRegistry(s.BSONRegistry(), values[i])
+		if err != nil {
+			return err
+		}
+
+		// decoding keeps other fields of the document
+		if err := bson.UnmarshalWithRegistry(s.BSONRegistry(), b, docs[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// populateBatch() loads related documents by one $in query
+func (s *BaseCollection) populateBatch(ctx context.Context, r Relation, raws []bson.Raw, values []bson.D) error {
+	var keys bson.A
+
+	seen := make(map[string]bool)
+
+	for _, raw := range raws {
+		for _, v := range relationKeys(raw, r.LocalField) {
+			if k := rawValueKey(v); !seen[k] {
+				seen[k] = true
+				keys = append(keys, v)
+			}
+		}
+	}
+
+	related := make(map[string][]bson.Raw)
+
+	if len(keys) > 0 {
+		var found []bson.Raw
+
+		err := s.related(r).FindAllByFilter(s.nested(ctx), bson.M{r.ForeignField: bson.M{"$in": keys}}, &found)
+		if err != nil {
+			return err
+		}
+
+		for _, doc := range found {
+			for _, v := range relationKeys(doc, r.ForeignField) {
+				k := rawValueKey(v)
+				related[k] = append(related[k], doc)
+			}
+		}
+	}
+
+	for i, raw := range raws {
+		var matched bson.A
+
+		added := make(map[string]bool)
+
+		for _, v := range relationKeys(raw, r.LocalField) {
+			for _, doc := range related[rawValueKey(v)] {
+				// documents related by several keys are added once
+				if k := string(doc); !added[k] {
+					added[k] = true
+					matched = append(matched, doc)
+				}
+			}
+		}
+
+		values[i] = appendRelated(values[i], r, matched)
+	}
+
+	return nil
+}
+
+// populateLookup() loads related documents of the relations by one $lookup aggregation
+func (s *BaseCollection) populateLookup(ctx context.Context, relations []Relation, raws []bson.Raw, values []bson.D) error {
+	ids := make(bson.A, 0, len(raws))
+	for _, raw := range raws {
+		ids = append(ids, raw.Lookup(CollectionIDKey))
+	}
+
+	pipeline := bson.A{bson.M{"$match": bson.M{CollectionIDKey: bson.M{"$in": ids}}}}
+	project := bson.M{}
+
+	for _, r := range relations {
+		stage, err := s.lookupStage(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		pipeline = append(pipeline, stage)
+		project[r.As] = 1
+	}
+
+	pipeline = append(pipeline, bson.M{"$project": project})
+
+	coll, err := s.ResolveCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	var found []bson.Raw
+
+	err = s.retry(ctx, PopulateMethod, false, func(ctx context.Context) error {
+		filterCtx, filterCancel := withTimeout(ctx, s.timeouts(ctx).Filter)
+		defer filterCancel()
+
+		cur, err := coll.Aggregate(filterCtx, pipeline)
+		if err != nil {
+			return err
+		}
+
+		return cur.All(filterCtx, &found)
+	})
+	if err != nil {
+		return HandleError(err)
+	}
+
+	byID := make(map[string]bson.Raw, len(found))
+	for _, doc := range found {
+		byID[rawValueKey(doc.Lookup(CollectionIDKey))] = doc
+	}
+
+	for i, raw := range raws {
+		doc, ok := byID[rawValueKey(raw.Lookup(CollectionIDKey))]
+		if !ok {
+			continue
+		}
+
+		for _, r := range relations {
+			var matched bson.A
+
+			if arr, ok := doc.Lookup(r.As).ArrayOK(); ok {
+				elems, err := arr.Values()
+				if err != nil {
+					return err
+				}
+
+				for _, el := range elems {
+					matched = append(matched, el.Document())
+				}
+			}
+
+			values[i] = appendRelated(values[i], r, matched)
+		}
+	}
+
+	return nil
+}
+
+// lookupStage() returns $lookup stage matching local IDs or arrays of IDs,
+// tenancy and default scopes of Target are applied, hooks of Target don't run within the aggregation
+func (s *BaseCollection) lookupStage(ctx context.Context, r Relation) (bson.M, error) {
+	related := s.related(r)
+
+	coll, err := related.ResolveCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// missing and null local fields match nothing, single IDs are wrapped into arrays
+	localKeys := bson.M{"$cond": bson.A{
+		bson.M{"$isArray": "$$local"},
+		"$$local",
+		bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$$local", nil}}, nil}},
+			bson.A{},
+			bson.A{"$$local"},
+		}},
+	}}
+
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"$expr": bson.M{"$in": bson.A{"$" + r.ForeignField, localKeys}}}},
+	}
+
+	scope, err := related.scopeFilter(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope != nil {
+		pipeline = append(pipeline, bson.M{"$match": scope})
+	}
+
+	return bson.M{"$lookup": bson.M{
+		"from":     coll.Name(),
+		"let":      bson.M{"local": "$" + r.LocalField},
+		"pipeline": pipeline,
+		"as":       r.As,
+	}}, nil
+}
+
+// withoutRelations() returns the document without fields receiving related documents,
+// so populated documents are not written back by updates
+func (s *BaseCollection) withoutRelations(doc interface{}) (interface{}, error) {
+	if len(s.Relations) == 0 {
+		return doc, nil
+	}
+
+	b, err := bson.MarshalWithRegistry(s.BSONRegistry(), doc)
+	if err != nil {
+		return nil, err
+	}
+
+	elems, err := bson.Raw(b).Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	d := make(bson.D, 0, len(elems))
+	for _, el := range elems {
+		if !s.isRelationKey(el.Key()) {
+			d = append(d, bson.E{Key: el.Key(), Value: el.Value()})
+		}
+	}
+
+	return d, nil
+}
+
+// withoutRelationKeys() drops fields of related documents and their nested keys from the update
+func (s *BaseCollection) withoutRelationKeys(fields bson.D) bson.D {
+	d := make(bson.D, 0, len(fields))
+	for _, e := range fields {
+		if !s.isRelationKey(e.Key) {
+			d = append(d, e)
+		}
+	}
+
+	return d
+}
+
+func (s *BaseCollection) isRelationKey(key string) bool {
+	for _, r := range s.Relations {
+		if key == r.As || strings.HasPrefix(key, r.As+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// related() returns Target of the relation or the collection sharing the client and settings of the collection
+func (s *BaseCollection) related(r Relation) *BaseCollection {
+	if r.Target != nil {
+		return r.Target
+	}
+
+	related := NewBaseCollectionWithClient(s.Client, s.DBName, r.Collection)
+	related.Timeouts = s.Timeouts
+	related.Tenancy = s.Tenancy
+	related.RetryPolicy = s.RetryPolicy
+
+	return related
+}
+
+// appendRelated() adds related documents to the fields decoded into the document
+func appendRelated(fields bson.D, r Relation, matched bson.A) bson.D {
+	if r.Many {
+		if matched == nil {
+			matched = bson.A{}
+		}
+
+		return append(fields, bson.E{Key: r.As, Value: matched})
+	}
+
+	if len(matched) == 0 {
+		return fields
+	}
+
+	return append(fields, bson.E{Key: r.As, Value: matched[0]})
+}
+
+// relationKeys() returns the value of the field or elements of the array field
+func relationKeys(raw bson.Raw, key string) []bson.RawValue {
+	v, err := raw.LookupErr(strings.Split(key, ".")...)
+	if err != nil || v.Type == bsontype.Null {
+		return nil
+	}
+
+	arr, ok := v.ArrayOK()
+	if !ok {
+		return []bson.RawValue{v}
+	}
+
+	values, err := arr.Values()
+	if err != nil {
+		return nil
+	}
+
+	return values
+}
+
+// rawValueKey() returns a comparable key of the BSON value
+func rawValueKey(v bson.RawValue) string {
+	return string(append([]byte{byte(v.Type)}, v.Value...))
+}
@@ -0,0 +1,246 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	. "github.com/wajox/mongol"
+)
+
+type RelatedAuthor struct {
+	BaseDocument `bson:",inline"`
+
+	Name string `bson:"name"`
+}
+
+type RelatedComment struct {
+	BaseDocument `bson:",inline"`
+
+	PostID primitive.ObjectID `bson:"post_id"`
+	Text   string             `bson:"text"`
+}
+
+type RelatedPost struct {
+	BaseDocument `bson:",inline"`
+
+	Title     string               `bson:"title"`
+	AuthorID  primitive.ObjectID   `bson:"author_id"`
+	EditorIDs []primitive.ObjectID `bson:"editor_ids"`
+
+	Author   *RelatedAuthor    `bson:"author,omitempty"`
+	Editors  []*RelatedAuthor  `bson:"editors,omitempty"`
+	Comments []*RelatedComment `bson:"comments,omitempty"`
+}
+
+var _ = Describe("Relations", func() {
+	var (
+		posts *BaseCollection
+	)
+
+	BeforeEach(func() {
+		posts = newTestCollection("relations_posts_test")
+
+		posts.AddRelation("author", Relation{Collection: "relations_authors_test", LocalField: "author_id"})
+		posts.AddRelation("editors", Relation{Collection: "relations_authors_test", LocalField: "editor_ids", Many: true})
+		posts.AddRelation("comments", Relation{
+			Collection:   "relations_comments_test",
+			LocalField:   "_id",
+			ForeignField: "post_id",
+			Many:         true,
+		})
+	})
+
+	Describe("WithPopulate()", func() {
+		It("should accumulate relation names", func() {
+			ctx := WithPopulate(context.TODO(), "author")
+			ctx = WithPopulate(ctx, "editors", "comments")
+
+			Expect(PopulateFromContext(ctx)).To(Equal([]string{"author", "editors", "comments"}))
+			Expect(PopulateFromContext(context.TODO())).To(BeEmpty())
+		})
+	})
+
+	Describe("AddRelation()", func() {
+		It("should set default keys", func() {
+			r := posts.Relations["author"]
+			Expect(r.ForeignField).To(Equal(CollectionIDKey))
+			Expect(r.As).To(Equal("author"))
+			Expect(r.Strategy).To(Equal(PopulateBatch))
+
+			Expect(posts.Relations["comments"].ForeignField).To(Equal("post_id"))
+		})
+
+		It("should take the collection name of the target", func() {
+			authors := NewBaseCollectionWithClient(posts.Client, posts.DBName, "relations_authors_test")
+			posts.AddRelation("reviewer", Relation{Target: authors, LocalField: "reviewer_id"})
+
+			Expect(posts.Relations["reviewer"].Collection).To(Equal("relations_authors_test"))
+		})
+	})
+
+	Describe("RelationNames()", func() {
+		It("should return sorted names", func() {
+			Expect(posts.RelationNames()).To(Equal([]string{"author", "comments", "editors"}))
+		})
+	})
+
+	// nolint
+	Describe("populate", func() {
+		var (
+			authors  *BaseCollection
+			comments *BaseCollection
+			post     *RelatedPost
+			author   *RelatedAuthor
+			editor   *RelatedAuthor
+		)
+
+		BeforeEach(func() {
+			authors = NewBaseCollectionWithClient(posts.Client, posts.DBName, "relations_authors_test")
+			comments = NewBaseCollectionWithClient(posts.Client, posts.DBName, "relations_comments_test")
+
+			for _, s := range []*BaseCollection{posts, authors, comments} {
+				s.DeleteAll(context.TODO())
+			}
+
+			author = &RelatedAuthor{Name: "author"}
+			editor = &RelatedAuthor{Name: "editor"}
+
+			authorID := insertRelated(authors, author)
+			editorID := insertRelated(authors, editor)
+
+			post = &RelatedPost{Title: "post", AuthorID: authorID, EditorIDs: []primitive.ObjectID{editorID, authorID}}
+			postID := insertRelated(posts, post)
+
+			_, err := posts.InsertOne(context.TODO(), &RelatedPost{Title: "draft", AuthorID: authorID})
+			Expect(err).To(BeNil())
+
+			_, err = comments.InsertMany(context.TODO(), []interface{}{
+				&RelatedComment{PostID: postID, Text: "first"},
+				&RelatedComment{PostID: postID, Text: "second"},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			for _, s := range []*BaseCollection{posts, authors, comments} {
+				s.Unscoped().DeleteAll(context.TODO())
+			}
+		})
+
+		for _, strategy := range []PopulateStrategy{PopulateBatch, PopulateLookup} {
+			strategy := strategy
+
+			It("should load related documents", func() {
+				for _, name := range posts.RelationNames() {
+					r := posts.Relations[name]
+					r.Strategy = strategy
+					posts.AddRelation(name, r)
+				}
+
+				ctx := WithPopulate(context.TODO(), "author", "editors", "comments")
+
+				m := &RelatedPost{}
+				Expect(posts.GetOneByID(ctx, post.GetHexID(), m)).To(BeNil())
+
+				Expect(m.Title).To(Equal("post"))
+				Expect(m.Author).NotTo(BeNil())
+				Expect(m.Author.Name).To(Equal("author"))
+				Expect(m.Editors).To(HaveLen(2))
+				Expect(m.Editors[0].Name).To(Equal("editor"))
+				Expect(m.Editors[1].Name).To(Equal("author"))
+				Expect(m.Comments).To(HaveLen(2))
+
+				docs, err := posts.GetManyByFilter(ctx, bson.M{}, func() Document { return &RelatedPost{} })
+				Expect(err).To(BeNil())
+				Expect(docs).To(HaveLen(2))
+
+				for _, doc := range docs {
+					p := doc.(*RelatedPost)
+					Expect(p.Author).NotTo(BeNil())
+
+					if p.Title == "draft" {
+						Expect(p.Editors).To(BeEmpty())
+						Expect(p.Comments).To(BeEmpty())
+					}
+				}
+			})
+		}
+
+		It("should not store populated documents on update", func() {
+			m := &RelatedPost{}
+			Expect(posts.GetOneByID(WithPopulate(context.TODO(), "author", "comments"), post.GetHexID(), m)).To(BeNil())
+			Expect(m.Author).NotTo(BeNil())
+
+			m.Title = "updated"
+			Expect(posts.UpdateOne(context.TODO(), m)).To(BeNil())
+
+			m.Title = "saved"
+			Expect(posts.Save(context.TODO(), m)).To(BeNil())
+
+			raw, err := posts.Collection().FindOne(context.TODO(), bson.M{"_id": post.ID}).DecodeBytes()
+			Expect(err).To(BeNil())
+
+			Expect(raw.Lookup("title").StringValue()).To(Equal("saved"))
+			Expect(raw.Lookup("author").Type).To(BeZero())
+			Expect(raw.Lookup("comments").Type).To(BeZero())
+		})
+
+		It("should not store populated documents on bulk replace", func() {
+			m := &RelatedPost{}
+			Expect(posts.GetOneByID(WithPopulate(context.TODO(), "author", "comments"), post.GetHexID(), m)).To(BeNil())
+			Expect(m.Author).NotTo(BeNil())
+
+			m.Title = "replaced"
+			_, err := posts.BulkWrite(context.TODO(), NewBulkWriteBuilder().ReplaceOne(bson.M{"_id": post.ID}, m))
+			Expect(err).To(BeNil())
+
+			raw, err := posts.Collection().FindOne(context.TODO(), bson.M{"_id": post.ID}).DecodeBytes()
+			Expect(err).To(BeNil())
+
+			Expect(raw.Lookup("title").StringValue()).To(Equal("replaced"))
+			Expect(raw.Lookup("author").Type).To(BeZero())
+			Expect(raw.Lookup("comments").Type).To(BeZero())
+		})
+
+		for _, strategy := range []PopulateStrategy{PopulateBatch, PopulateLookup} {
+			strategy := strategy
+
+			It("should apply default scopes of the target collection", func() {
+				authors.AddDefaultScope(bson.M{"name": "editor"})
+				posts.AddRelation("editors", Relation{Target: authors, LocalField: "editor_ids", Many: true, Strategy: strategy})
+
+				m := &RelatedPost{}
+				Expect(posts.GetOneByID(WithPopulate(context.TODO(), "editors"), post.GetHexID(), m)).To(BeNil())
+
+				Expect(m.Editors).To(HaveLen(1))
+				Expect(m.Editors[0].Name).To(Equal("editor"))
+			})
+		}
+
+		It("should not populate without context", func() {
+			m := &RelatedPost{}
+			Expect(posts.GetOneByID(context.TODO(), post.GetHexID(), m)).To(BeNil())
+			Expect(m.Author).To(BeNil())
+		})
+
+		It("should return an error for unknown relation", func() {
+			err := posts.GetOneByID(WithPopulate(context.TODO(), "unknown"), post.GetHexID(), &RelatedPost{})
+			Expect(errors.Is(err, ErrRelationNotRegistered)).To(BeTrue())
+		})
+	})
+})
+
+func insertRelated(s *BaseCollection, m Document) primitive.ObjectID {
+	id, err := s.InsertOne(context.TODO(), m)
+	Expect(err).To(BeNil())
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	Expect(err).To(BeNil())
+
+	return oid
+}
@@ -0,0 +1,226 @@
+package mongol
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// RetryMethod is a pseudo method whose before hooks run before every retry attempt,
+	// use RetryAttemptFromContext() to get the attempt number
+	RetryMethod = "Retry"
+
+	DefaultRetryMaxAttempts    = 3
+	DefaultRetryInitialBackoff = time.Millisecond * 50
+	DefaultRetryMaxBackoff     = time.Second * 2
+	DefaultRetryMultiplier     = 2
+	DefaultRetryJitter         = 0.5
+)
+
+// server error codes treated as transient by IsRetryableError()
+const (
+	HostUnreachableErrorCode                 = 6
+	HostNotFoundErrorCode                    = 7
+	NetworkTimeoutErrorCode                  = 89
+	ShutdownInProgressErrorCode              = 91
+	WriteConflictErrorCode                   = 112
+	PrimarySteppedDownErrorCode              = 189
+	SocketExceptionErrorCode                 = 9001
+	NotWritablePrimaryErrorCode              = 10107
+	InterruptedAtShutdownErrorCode           = 11600
+	InterruptedDueToReplStateChangeErrorCode = 11602
+	NotPrimaryNoSecondaryOkErrorCode         = 13435
+	NotPrimaryOrSecondaryErrorCode           = 13436
+)
+
+var (
+	retryableErrorCodes = []int{
+		HostUnreachableErrorCode,
+		HostNotFoundErrorCode,
+		NetworkTimeoutErrorCode,
+		ShutdownInProgressErrorCode,
+		WriteConflictErrorCode,
+		PrimarySteppedDownErrorCode,
+		SocketExceptionErrorCode,
+		NotWritablePrimaryErrorCode,
+		InterruptedAtShutdownErrorCode,
+		InterruptedDueToReplStateChangeErrorCode,
+		NotPrimaryNoSecondaryOkErrorCode,
+		NotPrimaryOrSecondaryErrorCode,
+	}
+
+	retryableErrorLabels = []string{
+		"TransientTransactionError",
+		"RetryableWriteError",
+	}
+
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// RetryPolicy retries operations of BaseCollection failed with transient errors
+// Reads are retried always, writes only if RetryWrites is set
+type RetryPolicy struct {
+	// MaxAttempts including the first one, the policy is disabled if it is less than 2
+	MaxAttempts int
+	// InitialBackoff is a delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff limits the delay between attempts
+	MaxBackoff time.Duration
+	// Multiplier of the delay after every attempt
+	Multiplier float64
+	// Jitter is a random part of the delay from 0 to 1
+	Jitter float64
+	// RetryWrites enables retries of writes, the write may be applied twice if the server
+	// failed after applying it, e.g. InsertOne() may fail with ErrDocumentDuplication
+	RetryWrites bool
+	// Retryable classifies errors, IsRetryableError() is used if nil
+	Retryable func(err error) bool
+	// OnRetry is called before every retry attempt
+	OnRetry func(ctx context.Context, method string, attempt int, err error)
+}
+
+type retryAttemptCtxKey struct{}
+
+// DefaultRetryPolicy() returns a policy based on package constants
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    DefaultRetryMaxAttempts,
+		InitialBackoff: DefaultRetryInitialBackoff,
+		MaxBackoff:     DefaultRetryMaxBackoff,
+		Multiplier:     DefaultRetryMultiplier,
+		Jitter:         DefaultRetryJitter,
+	}
+}
+
+// RetryAttemptFromContext() returns the number of the retry attempt, 0 for the first attempt
+func RetryAttemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(retryAttemptCtxKey{}).(int)
+
+	return attempt
+}
+
+// IsRetryableError() returns true for network errors, primary changes, write conflicts
+// and errors labelled as transient by the server
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if mongo.IsNetworkError(err) || errors.Is(err, ErrNetwork) || errors.Is(err, ErrWriteConflict) {
+		return true
+	}
+
+	var se mongo.ServerError
+	if !errors.As(err, &se) {
+		return false
+	}
+
+	for _, code := range retryableErrorCodes {
+		if se.HasErrorCode(code) {
+			return true
+		}
+	}
+
+	for _, label := range retryableErrorLabels {
+		if se.HasErrorLabel(label) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Backoff() returns the delay before the retry attempt
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitterMu.Lock()
+		d -= d * math.Min(p.Jitter, 1) * jitterRand.Float64()
+		jitterMu.Unlock()
+	}
+
+	return time.Duration(d)
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	return IsRetryableError(err)
+}
+
+// SetRetryPolicy() sets the retry policy of the collection, nil disables retries
+func (s *BaseCollection) SetRetryPolicy(p *RetryPolicy) {
+	s.RetryPolicy = p
+}
+
+// retry() calls fn until it succeeds, fails with a non-retryable error or attempts are exhausted
+// attempts are reported to RetryMethod hooks, RetryPolicy.OnRetry and Operation.Retries
+func (s *BaseCollection) retry(ctx context.Context, method string, write bool, fn func(ctx context.Context) error) error {
+	p := s.RetryPolicy
+	if p == nil || (write && !p.RetryWrites) {
+		return fn(ctx)
+	}
+
+	err := fn(ctx)
+
+	for attempt := 1; attempt < p.MaxAttempts && err != nil && p.retryable(err); attempt++ {
+		if waitErr := sleepContext(ctx, p.Backoff(attempt)); waitErr != nil {
+			return err
+		}
+
+		attemptCtx := context.WithValue(ctx, retryAttemptCtxKey{}, attempt)
+
+		if p.OnRetry != nil {
+			p.OnRetry(attemptCtx, method, attempt, err)
+		}
+
+		if op, ok := ctx.Value(operationCtxKey{}).(*Operation); ok {
+			op.Retries++
+		}
+
+		for _, h := range s.BeforeHooks[RetryMethod] {
+			if hookErr := h(attemptCtx); hookErr != nil {
+				return hookErr
+			}
+		}
+
+		err = fn(attemptCtx)
+	}
+
+	return err
+}
+
+// sleepContext() waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,137 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("RetryPolicy", func() {
+	Describe("IsRetryableError()", func() {
+		It("should detect transient errors", func() {
+			Expect(IsRetryableError(mongo.CommandError{Code: NotWritablePrimaryErrorCode})).To(BeTrue())
+			Expect(IsRetryableError(fmt.Errorf("update: %w", mongo.CommandError{Code: WriteConflictErrorCode}))).To(BeTrue())
+			Expect(IsRetryableError(mongo.CommandError{Labels: []string{"TransientTransactionError"}})).To(BeTrue())
+			Expect(IsRetryableError(mongo.WriteException{
+				WriteErrors: mongo.WriteErrors{{Code: WriteConflictErrorCode}},
+			})).To(BeTrue())
+		})
+
+		It("should not retry other errors", func() {
+			Expect(IsRetryableError(nil)).To(BeFalse())
+			Expect(IsRetryableError(ErrDocumentNotFound)).To(BeFalse())
+			Expect(IsRetryableError(context.DeadlineExceeded)).To(BeFalse())
+			Expect(IsRetryableError(mongo.CommandError{Code: DuplicationErrorCode})).To(BeFalse())
+		})
+	})
+
+	Describe("Backoff()", func() {
+		It("should grow exponentially up to MaxBackoff", func() {
+			p := DefaultRetryPolicy()
+			p.Jitter = 0
+
+			Expect(p.Backoff(1)).To(Equal(DefaultRetryInitialBackoff))
+			Expect(p.Backoff(2)).To(Equal(DefaultRetryInitialBackoff * 2))
+			Expect(p.Backoff(10)).To(Equal(DefaultRetryMaxBackoff))
+		})
+
+		It("should add jitter", func() {
+			p := DefaultRetryPolicy()
+
+			for i := 0; i < 10; i++ {
+				d := p.Backoff(2)
+				Expect(d).To(BeNumerically(">=", DefaultRetryInitialBackoff))
+				Expect(d).To(BeNumerically("<=", DefaultRetryInitialBackoff*2))
+			}
+		})
+	})
+
+	Describe("BaseCollection", func() {
+		var (
+			storage *BaseCollection
+			policy  *RetryPolicy
+			retried []int
+		)
+
+		BeforeEach(func() {
+			var err error
+			// nothing listens on the port, so every attempt fails with server selection error
+			storage, err = NewBaseCollection(
+				context.TODO(),
+				"mongodb://127.0.0.1:1",
+				"base_models_db_test",
+				"retry_test",
+				WithServerSelectionTimeout(time.Millisecond*10),
+			)
+			Expect(err).To(BeNil())
+
+			retried = nil
+
+			policy = &RetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: time.Millisecond,
+				Retryable:      func(err error) bool { return true },
+				OnRetry: func(ctx context.Context, method string, attempt int, err error) {
+					Expect(method).To(Equal(CountByFilterMethod))
+					retried = append(retried, attempt)
+				},
+			}
+			storage.SetRetryPolicy(policy)
+		})
+
+		It("should retry reads", func() {
+			hookAttempts := []int{}
+			storage.AddBeforeHook(RetryMethod, func(ctx context.Context) error {
+				hookAttempts = append(hookAttempts, RetryAttemptFromContext(ctx))
+				return nil
+			})
+
+			var op *Operation
+			instrumented := NewInstrumentedCollection(storage, InstrumentationFuncs{
+				Finish: func(ctx context.Context, o *Operation) { op = o },
+			})
+
+			_, err := instrumented.CountByFilter(context.TODO(), bson.M{})
+			Expect(err).NotTo(BeNil())
+
+			Expect(retried).To(Equal([]int{1, 2}))
+			Expect(hookAttempts).To(Equal([]int{1, 2}))
+			Expect(op.Retries).To(Equal(2))
+		})
+
+		It("should stop when the retry hook fails", func() {
+			hookErr := errors.New("stop")
+			storage.AddBeforeHook(RetryMethod, func(ctx context.Context) error {
+				return hookErr
+			})
+
+			_, err := storage.CountByFilter(context.TODO(), bson.M{})
+			Expect(errors.Is(err, hookErr)).To(BeTrue())
+			Expect(retried).To(Equal([]int{1}))
+		})
+
+		It("should not retry writes by default", func() {
+			policy.OnRetry = func(ctx context.Context, method string, attempt int, err error) {
+				retried = append(retried, attempt)
+			}
+
+			_, err := storage.DeleteManyByFilter(context.TODO(), bson.M{})
+			Expect(err).NotTo(BeNil())
+			Expect(retried).To(BeEmpty())
+
+			policy.RetryWrites = true
+
+			_, err = storage.DeleteManyByFilter(context.TODO(), bson.M{})
+			Expect(err).NotTo(BeNil())
+			Expect(retried).To(Equal([]int{1, 2}))
+		})
+	})
+})
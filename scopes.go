@@ -0,0 +1,132 @@
+package mongol
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ScopeFunc adds conditions of a named scope to the filter builder
+type ScopeFunc func(fb *FilterBuilder) *FilterBuilder
+
+// AddDefaultScope() adds a filter which is merged into every read, update and delete filter
+// of the collection, e.g. bson.M{"status": bson.M{"$ne": "archived"}} or *FilterBuilder,
+// operations fail with the error of the builder if it has unresolved field paths
+func (s *BaseCollection) AddDefaultScope(filter interface{}) {
+	s.DefaultScopes = append(s.DefaultScopes, filter)
+}
+
+// AddScope() registers a named scope, see Scope()
+func (s *BaseCollection) AddScope(name string, f ScopeFunc) {
+	if s.Scopes == nil {
+		s.Scopes = make(map[string]ScopeFunc)
+	}
+
+	s.Scopes[name] = f
+}
+
+// Scope() applies the named scope to the filter builder, a new builder is created if fb is nil,
+// the error of field paths resolution of the builder is returned
+func (s *BaseCollection) Scope(name string, fb *FilterBuilder) (*FilterBuilder, error) {
+	f, ok := s.Scopes[name]
+	if !ok {
+		return nil, ErrScopeNotRegistered
+	}
+
+	if fb == nil {
+		fb = NewFilterBuilder()
+	}
+
+	fb = f(fb)
+	if err := fb.Err(); err != nil {
+		return nil, err
+	}
+
+	return fb, nil
+}
+
+// MustScope() applies the named scope to the filter builder and panics if Scope() fails
+func (s *BaseCollection) MustScope(name string, fb *FilterBuilder) *FilterBuilder {
+	fb, err := s.Scope(name, fb)
+	if err != nil {
+		panic(err)
+	}
+
+	return fb
+}
+
+// ScopeNames() returns sorted names of the registered scopes
+func (s *BaseCollection) ScopeNames() []string {
+	names := make([]string, 0, len(s.Scopes))
+	for name := range s.Scopes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Unscoped() returns a copy of the collection which ignores default scopes,
+// hooks, scopes and relations are copied, so changes of the copy do not affect the collection
+// Tenancy is still applied
+func (s *BaseCollection) Unscoped() *BaseCollection {
+	u := *s
+	u.unscoped = true
+	// the client is released by the original collection
+	u.ownsClient = false
+
+	u.BeforeHooks = copyHooks(s.BeforeHooks)
+	u.AfterHooks = copyHooks(s.AfterHooks)
+	u.DefaultScopes = append([]interface{}(nil), s.DefaultScopes...)
+
+	u.Scopes = make(map[string]ScopeFunc, len(s.Scopes))
+	for name, f := range s.Scopes {
+		u.Scopes[name] = f
+	}
+
+	u.Relations = make(map[string]Relation, len(s.Relations))
+	for name, r := range s.Relations {
+		u.Relations[name] = r
+	}
+
+	return &u
+}
+
+func copyHooks(hooks map[string][]Hook) map[string][]Hook {
+	c := make(map[string][]Hook, len(hooks))
+	for method, h := range hooks {
+		c[method] = append([]Hook(nil), h...)
+	}
+
+	return c
+}
+
+// defaultScopes() returns default scopes applied to filters of the collection
+func (s *BaseCollection) defaultScopes() []interface{} {
+	if s.unscoped || len(s.DefaultScopes) == 0 {
+		return nil
+	}
+
+	scopes := make([]interface{}, len(s.DefaultScopes))
+	copy(scopes, s.DefaultScopes)
+
+	return scopes
+}
+
+// mergeFilters() combines the filter with the scopes by $and
+func mergeFilters(filter interface{}, scopes []interface{}) interface{} {
+	if len(scopes) == 0 {
+		return filter
+	}
+
+	if filter != nil {
+		scopes = append([]interface{}{filter}, scopes...)
+	}
+
+	if len(scopes) == 1 {
+		return scopes[0]
+	}
+
+	return bson.M{"$and": bson.A(scopes)}
+}
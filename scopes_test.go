@@ -0,0 +1,154 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("Scopes", func() {
+	var (
+		storage *BaseCollection
+	)
+
+	BeforeEach(func() {
+		storage = newTestCollection("scopes_test")
+
+		storage.AddScope("titled", func(fb *FilterBuilder) *FilterBuilder {
+			return fb.HasField("title")
+		})
+	})
+
+	Describe("Scope()", func() {
+		It("should apply the named scope", func() {
+			fb, err := storage.Scope("titled", NewFilterBuilder().EqualTo("title", "test"))
+			Expect(err).To(BeNil())
+			Expect(fb.GetQuery()).To(Equal(bson.M{"title": bson.M{"$exists": true}}))
+
+			fb, err = storage.Scope("titled", nil)
+			Expect(err).To(BeNil())
+			Expect(fb.GetQuery()).To(HaveKey("title"))
+		})
+
+		It("should return an error for unknown scope", func() {
+			_, err := storage.Scope("unknown", nil)
+			Expect(err).To(Equal(ErrScopeNotRegistered))
+			Expect(func() { storage.MustScope("unknown", nil) }).To(Panic())
+		})
+
+		It("should return an error of field paths resolution", func() {
+			storage.AddScope("addressed", func(fb *FilterBuilder) *FilterBuilder {
+				return fb.HasField("Adress")
+			})
+
+			fb := NewFilterBuilder().WithResolver(NewFieldResolver(&ResolvedModel{}))
+
+			_, err := storage.Scope("addressed", fb)
+			Expect(errors.Is(err, ErrUnknownField)).To(BeTrue())
+		})
+	})
+
+	Describe("filter builders", func() {
+		It("should be rejected with unresolved field paths", func() {
+			fb := NewFilterBuilder().WithResolver(NewFieldResolver(&ResolvedModel{})).EqualTo("Titel", "test")
+
+			_, err := storage.CountByFilter(context.TODO(), fb)
+			Expect(errors.Is(err, ErrUnknownField)).To(BeTrue())
+
+			ub := NewUpdateBuilder().WithResolver(NewFieldResolver(&ResolvedModel{})).Set("Titel", "test")
+
+			_, err = storage.UpdateMany(context.TODO(), bson.M{}, ub)
+			Expect(errors.Is(err, ErrUnknownField)).To(BeTrue())
+
+			storage.AddDefaultScope(fb)
+
+			_, err = storage.CountByFilter(context.TODO(), bson.M{})
+			Expect(errors.Is(err, ErrUnknownField)).To(BeTrue())
+		})
+	})
+
+	Describe("ScopeNames()", func() {
+		It("should return sorted names", func() {
+			storage.AddScope("active", func(fb *FilterBuilder) *FilterBuilder { return fb })
+			Expect(storage.ScopeNames()).To(Equal([]string{"active", "titled"}))
+		})
+	})
+
+	Describe("Unscoped()", func() {
+		It("should not change the collection", func() {
+			storage.AddDefaultScope(bson.M{"status": "active"})
+
+			unscoped := storage.Unscoped()
+			Expect(unscoped).NotTo(BeIdenticalTo(storage))
+			Expect(unscoped.CollectionName).To(Equal(storage.CollectionName))
+			Expect(storage.DefaultScopes).To(HaveLen(1))
+		})
+
+		It("should copy scopes, relations and hooks", func() {
+			unscoped := storage.Unscoped()
+
+			unscoped.AddScope("active", func(fb *FilterBuilder) *FilterBuilder { return fb })
+			unscoped.AddRelation("author", Relation{Collection: "users", LocalField: "author_id"})
+			unscoped.AddBeforeHook(InsertOneMethod, func(context.Context) error { return nil })
+			unscoped.AddDefaultScope(bson.M{"status": "active"})
+
+			Expect(storage.ScopeNames()).To(Equal([]string{"titled"}))
+			Expect(storage.RelationNames()).To(BeEmpty())
+			Expect(storage.BeforeHooks).To(BeEmpty())
+			Expect(storage.DefaultScopes).To(BeEmpty())
+			Expect(unscoped.ScopeNames()).To(Equal([]string{"active", "titled"}))
+		})
+	})
+
+	// nolint
+	Describe("AddDefaultScope()", func() {
+		BeforeEach(func() {
+			storage.Unscoped().DeleteAll(context.TODO())
+
+			active := NewExampleModel()
+			archived := NewExampleModel()
+			archived.Title = "archived"
+
+			_, err := storage.InsertMany(context.TODO(), []interface{}{active, archived})
+			Expect(err).To(BeNil())
+
+			storage.AddDefaultScope(NewFilterBuilder().NotEqualTo("title", "archived"))
+		})
+
+		AfterEach(func() {
+			storage.Unscoped().DeleteAll(context.TODO())
+		})
+
+		It("should merge default scopes into filters", func() {
+			count, err := storage.CountByFilter(context.TODO(), bson.M{})
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(int64(1)))
+
+			err = storage.GetOneByFilter(context.TODO(), bson.M{"title": "archived"}, &ExampleModel{})
+			Expect(errors.Is(err, ErrDocumentNotFound)).To(BeTrue())
+
+			count, err = storage.Unscoped().CountByFilter(context.TODO(), bson.M{})
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(int64(2)))
+		})
+
+		It("should compose named scopes", func() {
+			count, err := storage.CountByFilter(context.TODO(), storage.MustScope("titled", nil).GetQuery())
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(int64(1)))
+		})
+
+		It("should delete only scoped documents", func() {
+			Expect(storage.DeleteAll(context.TODO())).To(BeNil())
+
+			count, err := storage.Unscoped().CountByFilter(context.TODO(), bson.M{})
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(int64(1)))
+		})
+	})
+})
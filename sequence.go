@@ -0,0 +1,152 @@
+package mongol
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// DefaultSequenceCollectionName is a name of the collection with sequence counters
+	DefaultSequenceCollectionName = "counters"
+	// SequenceValueKey is a field of the counter document with the last allocated value
+	SequenceValueKey = "seq"
+)
+
+// Sequence allocates monotonically increasing int64 values
+// stored in a counters collection as {_id: name, seq: last allocated value}
+// With BlockSize > 1 the values are reserved in blocks and handed out locally,
+// so values of one block are not shared between Sequence instances
+type Sequence struct {
+	Collection *BaseCollection
+	Name       string
+	BlockSize  int64
+
+	mu   sync.Mutex
+	next int64
+	last int64
+}
+
+type sequenceCounter struct {
+	Value int64 `bson:"seq"`
+}
+
+// NewSequence() is a constructor for Sequence struct
+func NewSequence(counters *BaseCollection, name string, blockSize int64) *Sequence {
+	if blockSize < 1 {
+		blockSize = 1
+	}
+
+	return &Sequence{
+		Collection: counters,
+		Name:       name,
+		BlockSize:  blockSize,
+	}
+}
+
+// Next() returns the next value of the sequence
+func (seq *Sequence) Next(ctx context.Context) (int64, error) {
+	seq.mu.Lock()
+	defer seq.mu.Unlock()
+
+	if seq.next == 0 || seq.next > seq.last {
+		first, err := seq.Reserve(ctx, seq.BlockSize)
+		if err != nil {
+			return 0, err
+		}
+
+		seq.next = first
+		seq.last = first + seq.BlockSize - 1
+	}
+
+	v := seq.next
+	seq.next++
+
+	return v, nil
+}
+
+// Reserve() atomically allocates n values in the database and returns the first one
+// The values are not handed out by Next(), n must be at least 1
+func (seq *Sequence) Reserve(ctx context.Context, n int64) (_ int64, err error) {
+	s := seq.Collection
+
+	defer s.wrapError(&err, SequenceReserveMethod, seq.Name)
+
+	if n < 1 {
+		return 0, ErrInvalidSequenceCount
+	}
+
+	if err := s.runBeforeHooks(ctx, SequenceReserveMethod); err != nil {
+		return 0, err
+	}
+
+	defer s.runAfterHooks(ctx, SequenceReserveMethod)
+
+	coll, filter, err := s.scope(ctx, bson.M{CollectionIDKey: seq.Name})
+	if err != nil {
+		return 0, err
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetUpsert(true)
+
+	counter := &sequenceCounter{}
+
+	// a retried $inc may skip a block, values are still unique
+	err = s.retry(ctx, SequenceReserveMethod, true, func(ctx context.Context) error {
+		queryCtx, queryCancel := withTimeout(ctx, s.timeouts(ctx).Query)
+		defer queryCancel()
+
+		return coll.FindOneAndUpdate(
+			queryCtx,
+			filter,
+			bson.M{"$inc": bson.M{SequenceValueKey: n}},
+			opts,
+		).Decode(counter)
+	})
+	if err != nil {
+		return 0, HandleError(err)
+	}
+
+	return counter.Value - n + 1, nil
+}
+
+// Current() returns the last allocated value stored in the database
+func (seq *Sequence) Current(ctx context.Context) (_ int64, err error) {
+	s := seq.Collection
+
+	defer s.wrapError(&err, SequenceCurrentMethod, seq.Name)
+
+	if err := s.runBeforeHooks(ctx, SequenceCurrentMethod); err != nil {
+		return 0, err
+	}
+
+	defer s.runAfterHooks(ctx, SequenceCurrentMethod)
+
+	coll, filter, err := s.scope(ctx, bson.M{CollectionIDKey: seq.Name})
+	if err != nil {
+		return 0, err
+	}
+
+	counter := &sequenceCounter{}
+
+	err = s.retry(ctx, SequenceCurrentMethod, false, func(ctx context.Context) error {
+		queryCtx, queryCancel := withTimeout(ctx, s.timeouts(ctx).Query)
+		defer queryCancel()
+
+		return coll.FindOne(queryCtx, filter).Decode(counter)
+	})
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, HandleError(err)
+	}
+
+	return counter.Value, nil
+}
@@ -0,0 +1,164 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/wajox/mongol"
+)
+
+type OrderModel struct {
+	Int64Document `bson:",inline"`
+
+	Title string `json:"title,omitempty" bson:"title,omitempty"`
+}
+
+// nolint
+var _ = Describe("Sequence", func() {
+	var (
+		counters *BaseCollection
+	)
+
+	BeforeEach(func() {
+		counters = newTestCollection(DefaultSequenceCollectionName)
+	})
+
+	Describe("NewSequence()", func() {
+		It("should create a sequence with block size at least 1", func() {
+			seq := NewSequence(counters, "orders", 0)
+
+			Expect(seq.Name).To(Equal("orders"))
+			Expect(seq.BlockSize).To(Equal(int64(1)))
+		})
+	})
+
+	Describe("errors", func() {
+		It("should reject a non-positive count", func() {
+			seq := NewSequence(counters, "orders", 1)
+
+			_, err := seq.Reserve(context.TODO(), 0)
+			Expect(errors.Is(err, ErrInvalidSequenceCount)).To(BeTrue())
+
+			var opErr *OperationError
+			Expect(errors.As(err, &opErr)).To(BeTrue())
+			Expect(opErr.Method).To(Equal(SequenceReserveMethod))
+			Expect(opErr.ID).To(Equal("orders"))
+		})
+
+		It("should scope the counters by tenant", func() {
+			counters.SetTenancy(&Tenancy{Mode: TenantPerField})
+			seq := NewSequence(counters, "orders", 1)
+
+			_, err := seq.Next(context.TODO())
+			Expect(errors.Is(err, ErrTenantMissing)).To(BeTrue())
+
+			var opErr *OperationError
+			Expect(errors.As(err, &opErr)).To(BeTrue())
+			Expect(opErr.Method).To(Equal(SequenceReserveMethod))
+
+			_, err = seq.Current(context.TODO())
+			Expect(errors.Is(err, ErrTenantMissing)).To(BeTrue())
+			Expect(errors.As(err, &opErr)).To(BeTrue())
+			Expect(opErr.Method).To(Equal(SequenceCurrentMethod))
+		})
+
+		It("should run the hooks", func() {
+			hookErr := errors.New("forbidden")
+			counters.AddBeforeHook(SequenceCurrentMethod, func(ctx context.Context) error {
+				return hookErr
+			})
+
+			_, err := NewSequence(counters, "orders", 1).Current(context.TODO())
+			Expect(errors.Is(err, hookErr)).To(BeTrue())
+		})
+	})
+
+	Describe("methods", func() {
+		AfterEach(func() {
+			counters.DeleteAll(context.TODO())
+		})
+
+		It("should allocate increasing values", func() {
+			seq := NewSequence(counters, "orders", 1)
+
+			for i := int64(1); i <= 3; i++ {
+				v, err := seq.Next(context.TODO())
+				Expect(err).To(BeNil())
+				Expect(v).To(Equal(i))
+			}
+
+			current, err := seq.Current(context.TODO())
+			Expect(err).To(BeNil())
+			Expect(current).To(Equal(int64(3)))
+		})
+
+		It("should allocate values in blocks", func() {
+			seq1 := NewSequence(counters, "orders", 10)
+			seq2 := NewSequence(counters, "orders", 10)
+
+			v1, err := seq1.Next(context.TODO())
+			Expect(err).To(BeNil())
+			Expect(v1).To(Equal(int64(1)))
+
+			v2, err := seq2.Next(context.TODO())
+			Expect(err).To(BeNil())
+			Expect(v2).To(Equal(int64(11)))
+
+			v1, err = seq1.Next(context.TODO())
+			Expect(err).To(BeNil())
+			Expect(v1).To(Equal(int64(2)))
+
+			first, err := seq1.Reserve(context.TODO(), 5)
+			Expect(err).To(BeNil())
+			Expect(first).To(Equal(int64(21)))
+		})
+
+		It("should not return duplicates concurrently", func() {
+			seq := NewSequence(counters, "orders", 3)
+			values := make(chan int64, 50)
+
+			wg := sync.WaitGroup{}
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer GinkgoRecover()
+
+					v, err := seq.Next(context.TODO())
+					Expect(err).To(BeNil())
+					values <- v
+				}()
+			}
+			wg.Wait()
+			close(values)
+
+			seen := map[int64]bool{}
+			for v := range values {
+				Expect(seen[v]).To(BeFalse())
+				seen[v] = true
+			}
+			Expect(len(seen)).To(Equal(50))
+		})
+
+		It("should generate IDs for Int64Document", func() {
+			orders := NewBaseCollectionWithClient(counters.Client, "base_models_db_test", "orders_test")
+			defer orders.DeleteAll(context.TODO())
+
+			orders.SetIDStrategy(Int64IDStrategy{Sequence: NewSequence(counters, "orders", 1)})
+
+			m := &OrderModel{Title: "First order"}
+			id, err := orders.InsertOne(context.TODO(), m)
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal("1"))
+			Expect(m.ID).To(Equal(int64(1)))
+
+			found := &OrderModel{}
+			Expect(orders.GetOneByID(context.TODO(), "1", found)).To(BeNil())
+			Expect(found.Title).To(Equal(m.Title))
+		})
+	})
+})
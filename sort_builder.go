@@ -0,0 +1,75 @@
+package mongol
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// SortAsc is the ascending sort order
+	SortAsc = 1
+	// SortDesc is the descending sort order
+	SortDesc = -1
+)
+
+// SortBuilder
+type SortBuilder struct {
+	sort     bson.D
+	resolver *FieldResolver
+	err      error
+}
+
+// NewSortBuilder() initializes a new SortBuilder
+func NewSortBuilder() *SortBuilder {
+	return &SortBuilder{
+		sort: bson.D{},
+	}
+}
+
+// WithResolver() makes the builder accept Go field paths of the model, e.g. "Address.City",
+// unknown paths are reported by Err()
+func (sb *SortBuilder) WithResolver(r *FieldResolver) *SortBuilder {
+	sb.resolver = r
+
+	return sb
+}
+
+// Err() returns the first error of field paths resolution
+func (sb *SortBuilder) Err() error {
+	return sb.err
+}
+
+// GetSort() returns compiled sort for options.Find().SetSort(), unresolved field paths are kept as is, see Build()
+func (sb *SortBuilder) GetSort() bson.D {
+	return sb.sort
+}
+
+// Build() returns compiled sort or the first error of field paths resolution
+func (sb *SortBuilder) Build() (bson.D, error) {
+	if sb.err != nil {
+		return nil, sb.err
+	}
+
+	return sb.sort, nil
+}
+
+// Asc() sorts by the field in ascending order
+func (sb *SortBuilder) Asc(key string) *SortBuilder {
+	return sb.add(key, SortAsc)
+}
+
+// Desc() sorts by the field in descending order
+func (sb *SortBuilder) Desc(key string) *SortBuilder {
+	return sb.add(key, SortDesc)
+}
+
+func (sb *SortBuilder) add(path string, order int) *SortBuilder {
+	key, err := resolveKey(sb.resolver, path)
+	if err != nil && sb.err == nil {
+		sb.err = err
+	}
+
+	sb.sort = append(sb.sort, primitive.E{Key: key, Value: order})
+
+	return sb
+}
@@ -0,0 +1,47 @@
+package mongol_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("SortBuilder", func() {
+	It("should keep the order of fields", func() {
+		sort := NewSortBuilder().Desc("created_at").Asc("title")
+
+		Expect(sort.Err()).To(BeNil())
+		Expect(sort.GetSort()).To(Equal(bson.D{
+			{Key: "created_at", Value: SortDesc},
+			{Key: "title", Value: SortAsc},
+		}))
+	})
+
+	Context("with field resolver", func() {
+		It("should resolve Go field paths", func() {
+			sort := NewSortBuilder().WithResolver(NewFieldResolver(&ResolvedModel{})).
+				Desc("CreatedAt").
+				Asc("Address.City")
+
+			Expect(sort.Err()).To(BeNil())
+			Expect(sort.GetSort()).To(Equal(bson.D{
+				{Key: "created_at", Value: SortDesc},
+				{Key: "addr.city", Value: SortAsc},
+			}))
+		})
+
+		It("should report unknown fields", func() {
+			sort := NewSortBuilder().WithResolver(NewFieldResolver(&ResolvedModel{})).Asc("Titel")
+
+			Expect(errors.Is(sort.Err(), ErrUnknownField)).To(BeTrue())
+
+			d, err := sort.Build()
+			Expect(d).To(BeNil())
+			Expect(errors.Is(err, ErrUnknownField)).To(BeTrue())
+		})
+	})
+})
@@ -8,7 +8,8 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// Storage
+// Storage declares data operations of a collection,
+// configuration methods like SetTimeouts() and AddScope() belong to *BaseCollection
 type Storage interface {
 	AddBeforeHook(methodName string, h Hook)
 	AddAfterHook(methodName string, h Hook)
@@ -19,8 +20,13 @@ type Storage interface {
 	CreateIndex(ctx context.Context, k interface{}, o *options.IndexOptions) (string, error)
 	InsertOne(ctx context.Context, m Document, opts ...*options.InsertOneOptions) (string, error)
 	InsertMany(ctx context.Context, docs []interface{}, opts ...*options.InsertManyOptions) ([]string, error)
+	BulkWrite(ctx context.Context, b *BulkWriteBuilder) (*BulkWriteResult, error)
 	UpdateOne(ctx context.Context, m Document, opts ...*options.UpdateOptions) error
 	UpdateManyByFilter(ctx context.Context, filter interface{}, m Document, opts ...*options.UpdateOptions) error
+	PatchOneByID(ctx context.Context, recordID string, patch []byte, m Document) error
+	Save(ctx context.Context, m Document, opts ...*options.UpdateOptions) error
+	UpdateOneWithResult(ctx context.Context, m Document, opts ...*options.UpdateOptions) (*WriteResult, error)
+	UpdateManyByFilterWithResult(ctx context.Context, filter interface{}, m Document, opts ...*options.UpdateOptions) (*WriteResult, error)
 	UpdateMany(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
 	UpsertOne(ctx context.Context, filter interface{}, update bson.M, m Document) (Document, error)
 	FindAndUpdateOne(ctx context.Context, filter interface{}, update bson.M, m Document) (Document, error)
@@ -31,8 +37,11 @@ type Storage interface {
 	GetManyByFilter(ctx context.Context, filter interface{}, modelBuilder func() Document, opts ...*options.FindOptions) ([]Document, error)
 	FindAllByFilter(ctx context.Context, filter interface{}, docs interface{}, opts ...*options.FindOptions) error
 	FindManyByFilter(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
+	Iterate(ctx context.Context, filter interface{}, modelBuilder func() Document, fn func(Document) error, opts ...*options.FindOptions) error
+	Iterator(ctx context.Context, filter interface{}, modelBuilder func() Document, opts ...*options.FindOptions) (*Iterator, error)
 	CountByFilter(ctx context.Context, filter interface{}) (int64, error)
 	DeleteManyByFilter(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
 	DeleteOneByID(ctx context.Context, docID string) error
+	DeleteOne(ctx context.Context, m Document) error
 	DeleteAll(ctx context.Context) error
 }
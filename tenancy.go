@@ -0,0 +1,231 @@
+package mongol
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// DefaultTenantField is a document field with the tenant ID for TenantPerField mode
+	DefaultTenantField = "tenant_id"
+)
+
+// TenantMode defines how the tenant data is separated
+type TenantMode int
+
+const (
+	// TenantPerDatabase keeps the collection of every tenant in its own database
+	TenantPerDatabase TenantMode = iota
+	// TenantPerCollection keeps every tenant in its own collection of the database
+	TenantPerCollection
+	// TenantPerField keeps all tenants in one collection and scopes documents by the tenant field
+	TenantPerField
+)
+
+// Tenancy configures tenant-aware routing of BaseCollection
+// The tenant ID is taken from the context, see WithTenant()
+type Tenancy struct {
+	Mode TenantMode
+	// Field with the tenant ID for TenantPerField mode, DefaultTenantField is used if empty
+	Field string
+	// Name returns a database or collection name of the tenant,
+	// base + "_" + tenantID is used if nil
+	Name func(base, tenantID string) string
+}
+
+type tenantCtxKey struct{}
+
+// WithTenant() returns a context carrying the tenant ID
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantCtxKey{}, tenantID)
+}
+
+// TenantFromContext() returns the tenant ID of the context
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantCtxKey{}).(string)
+
+	return tenantID, ok && tenantID != ""
+}
+
+func (t *Tenancy) field() string {
+	if t.Field == "" {
+		return DefaultTenantField
+	}
+
+	return t.Field
+}
+
+func (t *Tenancy) name(base, tenantID string) string {
+	if t.Name == nil {
+		return base + "_" + tenantID
+	}
+
+	return t.Name(base, tenantID)
+}
+
+// SetTenancy() enables tenant-aware routing of the collection, nil disables it
+func (s *BaseCollection) SetTenancy(t *Tenancy) {
+	s.Tenancy = t
+}
+
+// tenant() returns the tenant ID of the context, ErrTenantMissing is returned
+// if the collection is tenant-aware and the context has no tenant
+func (s *BaseCollection) tenant(ctx context.Context) (string, error) {
+	if s.Tenancy == nil {
+		return "", nil
+	}
+
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", ErrTenantMissing
+	}
+
+	return tenantID, nil
+}
+
+// ResolveCollection() returns *mongo.Collection of the tenant carried by the context
+// or Collection() if the collection is not tenant-aware
+func (s *BaseCollection) ResolveCollection(ctx context.Context) (*mongo.Collection, error) {
+	tenantID, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Tenancy == nil || tenantID == "" {
+		return s.Collection(), nil
+	}
+
+	switch s.Tenancy.Mode {
+	case TenantPerDatabase:
+		return s.MongoClient().Database(s.Tenancy.name(s.DBName, tenantID)).Collection(s.CollectionName), nil
+	case TenantPerCollection:
+		return s.Database().Collection(s.Tenancy.name(s.CollectionName, tenantID)), nil
+	default:
+		return s.Collection(), nil
+	}
+}
+
+// scope() resolves the collection and the filter of the tenant carried by the context
+func (s *BaseCollection) scope(ctx context.Context, filter interface{}) (*mongo.Collection, interface{}, error) {
+	coll, err := s.ResolveCollection(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter, err = s.scopeFilter(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return coll, filter, nil
+}
+
+// scopeFilter() merges default scopes into the filter and restricts it
+// to the documents of the tenant in TenantPerField mode,
+// filter builders with unresolved field paths are rejected
+func (s *BaseCollection) scopeFilter(ctx context.Context, filter interface{}) (interface{}, error) {
+	tenantID, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = buildFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := s.defaultScopes()
+	for i := range scopes {
+		if scopes[i], err = buildFilter(scopes[i]); err != nil {
+			return nil, err
+		}
+	}
+	if s.isTenantPerField() {
+		scopes = append(scopes, bson.M{s.Tenancy.field(): tenantID})
+	}
+
+	return mergeFilters(filter, scopes), nil
+}
+
+func (s *BaseCollection) isTenantPerField() bool {
+	return s.Tenancy != nil && s.Tenancy.Mode == TenantPerField
+}
+
+// scopeDocument() sets the tenant field of the inserted or replacing document in TenantPerField mode
+func (s *BaseCollection) scopeDocument(ctx context.Context, doc interface{}) (interface{}, error) {
+	tenantID, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isTenantPerField() {
+		return doc, nil
+	}
+
+	b, err := bson.MarshalWithRegistry(s.BSONRegistry(), doc)
+	if err != nil {
+		return nil, err
+	}
+
+	d := bson.D{}
+	if err := bson.UnmarshalWithRegistry(s.BSONRegistry(), b, &d); err != nil {
+		return nil, err
+	}
+
+	field := s.Tenancy.field()
+	for i := range d {
+		if d[i].Key == field {
+			d[i].Value = tenantID
+			return d, nil
+		}
+	}
+
+	return append(d, primitive.E{Key: field, Value: tenantID}), nil
+}
+
+// scopeWriteModel() applies scopeFilter() and scopeDocument() to the bulk write model,
+// filter and update builders are resolved even if the collection has no scopes
+func (s *BaseCollection) scopeWriteModel(ctx context.Context, model mongo.WriteModel) (mongo.WriteModel, error) {
+	var err error
+
+	switch m := model.(type) {
+	case *mongo.InsertOneModel:
+		scoped := *m
+		scoped.Document, err = s.scopeDocument(ctx, m.Document)
+		return &scoped, err
+	case *mongo.UpdateOneModel:
+		scoped := *m
+		if scoped.Filter, err = s.scopeFilter(ctx, m.Filter); err != nil {
+			return nil, err
+		}
+		scoped.Update, err = buildUpdate(m.Update)
+		return &scoped, err
+	case *mongo.UpdateManyModel:
+		scoped := *m
+		if scoped.Filter, err = s.scopeFilter(ctx, m.Filter); err != nil {
+			return nil, err
+		}
+		scoped.Update, err = buildUpdate(m.Update)
+		return &scoped, err
+	case *mongo.ReplaceOneModel:
+		scoped := *m
+		if scoped.Filter, err = s.scopeFilter(ctx, m.Filter); err != nil {
+			return nil, err
+		}
+		scoped.Replacement, err = s.scopeDocument(ctx, m.Replacement)
+		return &scoped, err
+	case *mongo.DeleteOneModel:
+		scoped := *m
+		scoped.Filter, err = s.scopeFilter(ctx, m.Filter)
+		return &scoped, err
+	case *mongo.DeleteManyModel:
+		scoped := *m
+		scoped.Filter, err = s.scopeFilter(ctx, m.Filter)
+		return &scoped, err
+	default:
+		return model, nil
+	}
+}
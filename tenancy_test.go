@@ -0,0 +1,132 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("Tenancy", func() {
+	var (
+		storage *BaseCollection
+	)
+
+	BeforeEach(func() {
+		storage = newTestCollection("tenancy_test")
+	})
+
+	Describe("TenantFromContext()", func() {
+		It("should return the tenant of the context", func() {
+			_, ok := TenantFromContext(context.TODO())
+			Expect(ok).To(BeFalse())
+
+			tenantID, ok := TenantFromContext(WithTenant(context.TODO(), "acme"))
+			Expect(ok).To(BeTrue())
+			Expect(tenantID).To(Equal("acme"))
+		})
+	})
+
+	Describe("ResolveCollection()", func() {
+		ctx := WithTenant(context.TODO(), "acme")
+
+		It("should resolve a database of the tenant", func() {
+			storage.SetTenancy(&Tenancy{Mode: TenantPerDatabase})
+
+			coll, err := storage.ResolveCollection(ctx)
+			Expect(err).To(BeNil())
+			Expect(coll.Database().Name()).To(Equal("base_models_db_test_acme"))
+			Expect(coll.Name()).To(Equal("tenancy_test"))
+		})
+
+		It("should resolve a collection of the tenant", func() {
+			storage.SetTenancy(&Tenancy{
+				Mode: TenantPerCollection,
+				Name: func(base, tenantID string) string {
+					return tenantID + "." + base
+				},
+			})
+
+			coll, err := storage.ResolveCollection(ctx)
+			Expect(err).To(BeNil())
+			Expect(coll.Database().Name()).To(Equal("base_models_db_test"))
+			Expect(coll.Name()).To(Equal("acme.tenancy_test"))
+		})
+
+		It("should resolve the shared collection without tenancy", func() {
+			coll, err := storage.ResolveCollection(context.TODO())
+			Expect(err).To(BeNil())
+			Expect(coll.Name()).To(Equal("tenancy_test"))
+		})
+	})
+
+	Context("without tenant in the context", func() {
+		BeforeEach(func() {
+			storage.SetTenancy(&Tenancy{Mode: TenantPerField})
+		})
+
+		It("should not touch shared data", func() {
+			_, err := storage.InsertOne(context.TODO(), NewExampleModel())
+			Expect(errors.Is(err, ErrTenantMissing)).To(BeTrue())
+
+			_, err = storage.CountByFilter(context.TODO(), bson.M{})
+			Expect(errors.Is(err, ErrTenantMissing)).To(BeTrue())
+
+			_, err = storage.DeleteManyByFilter(context.TODO(), bson.M{})
+			Expect(errors.Is(err, ErrTenantMissing)).To(BeTrue())
+
+			Expect(errors.Is(storage.GetOneByFilter(context.TODO(), bson.M{}, &ExampleModel{}), ErrTenantMissing)).To(BeTrue())
+			Expect(errors.Is(storage.DeleteAll(context.TODO()), ErrTenantMissing)).To(BeTrue())
+		})
+	})
+
+	// nolint
+	Describe("TenantPerField", func() {
+		var (
+			acme, globex context.Context
+		)
+
+		BeforeEach(func() {
+			storage.SetTenancy(&Tenancy{Mode: TenantPerField})
+
+			acme = WithTenant(context.TODO(), "acme")
+			globex = WithTenant(context.TODO(), "globex")
+		})
+
+		AfterEach(func() {
+			storage.SetTenancy(nil)
+			storage.DeleteAll(context.TODO())
+		})
+
+		It("should scope reads, writes and deletes by tenant", func() {
+			m := NewExampleModel()
+			id, err := storage.InsertOne(acme, m)
+			Expect(err).To(BeNil())
+
+			_, err = storage.InsertMany(globex, []interface{}{NewExampleModel(), NewExampleModel()})
+			Expect(err).To(BeNil())
+
+			Expect(storage.GetOneByID(acme, id, &ExampleModel{})).To(BeNil())
+			Expect(errors.Is(storage.GetOneByID(globex, id, &ExampleModel{}), ErrDocumentNotFound)).To(BeTrue())
+
+			count, err := storage.CountByFilter(globex, bson.M{})
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(int64(2)))
+
+			raw := bson.M{}
+			Expect(storage.Collection().FindOne(context.TODO(), bson.M{"_id": m.ID}).Decode(&raw)).To(BeNil())
+			Expect(raw[DefaultTenantField]).To(Equal("acme"))
+
+			Expect(errors.Is(storage.DeleteOneByID(globex, id), ErrDocumentNotFound)).To(BeTrue())
+			Expect(storage.DeleteAll(acme)).To(BeNil())
+
+			count, err = storage.CountByFilter(globex, bson.M{})
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(int64(2)))
+		})
+	})
+})
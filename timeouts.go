@@ -0,0 +1,84 @@
+package mongol
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// NoTimeout disables the timeout when it is used as a value of Timeouts fields
+	NoTimeout time.Duration = -1
+)
+
+// Timeouts of BaseCollection operations
+// Zero fields are not set and fall back to the collection settings or package defaults
+type Timeouts struct {
+	// CloseCursor limits closing of cursors
+	CloseCursor time.Duration
+	// Fetch limits reading of all documents from a cursor
+	Fetch time.Duration
+	// Query limits single document queries: GetOneByFilter(), FindAndUpdateOne(), UpsertOne(),
+	// only the caller deadline applies by default
+	Query time.Duration
+	// Filter limits Find requests of GetManyByFilter() and FindAllByFilter()
+	Filter time.Duration
+	// Count limits CountByFilter(), only the caller deadline applies by default
+	Count time.Duration
+}
+
+type timeoutsCtxKey struct{}
+
+// DefaultTimeouts() returns timeouts based on package constants,
+// operations without a client-side limit before Timeouts were added keep NoTimeout
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		CloseCursor: CloseCursorTimeout,
+		Fetch:       FetchTimeout,
+		Query:       NoTimeout,
+		Filter:      FilterTimeout,
+		Count:       NoTimeout,
+	}
+}
+
+// WithTimeouts() returns a context which overrides collection timeouts for a single call
+func WithTimeouts(ctx context.Context, t Timeouts) context.Context {
+	if prev, ok := ctx.Value(timeoutsCtxKey{}).(Timeouts); ok {
+		t = prev.Merge(t)
+	}
+
+	return context.WithValue(ctx, timeoutsCtxKey{}, t)
+}
+
+// Merge() returns a copy of timeouts with non-zero fields of o
+func (t Timeouts) Merge(o Timeouts) Timeouts {
+	if o.CloseCursor != 0 {
+		t.CloseCursor = o.CloseCursor
+	}
+
+	if o.Fetch != 0 {
+		t.Fetch = o.Fetch
+	}
+
+	if o.Query != 0 {
+		t.Query = o.Query
+	}
+
+	if o.Filter != 0 {
+		t.Filter = o.Filter
+	}
+
+	if o.Count != 0 {
+		t.Count = o.Count
+	}
+
+	return t
+}
+
+// withTimeout() derives a context from ctx limited by d, NoTimeout keeps only the caller deadline
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d < 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, d)
+}
@@ -0,0 +1,82 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("Timeouts", func() {
+	Describe("DefaultTimeouts()", func() {
+		It("should return package defaults", func() {
+			t := DefaultTimeouts()
+
+			Expect(t.CloseCursor).To(Equal(CloseCursorTimeout))
+			Expect(t.Fetch).To(Equal(FetchTimeout))
+			Expect(t.Query).To(Equal(NoTimeout))
+			Expect(t.Filter).To(Equal(FilterTimeout))
+			Expect(t.Count).To(Equal(NoTimeout))
+		})
+	})
+
+	Describe("Merge()", func() {
+		It("should override only non-zero fields", func() {
+			t := DefaultTimeouts().Merge(Timeouts{Fetch: time.Minute, Filter: NoTimeout})
+
+			Expect(t.Fetch).To(Equal(time.Minute))
+			Expect(t.Filter).To(Equal(NoTimeout))
+			Expect(t.CloseCursor).To(Equal(CloseCursorTimeout))
+		})
+	})
+
+	Describe("BaseCollection", func() {
+		var (
+			storage *BaseCollection
+		)
+
+		BeforeEach(func() {
+			storage = newTestCollection("timeouts_test")
+		})
+
+		Describe("SetTimeouts()", func() {
+			It("should keep not provided timeouts", func() {
+				storage.SetTimeouts(Timeouts{Fetch: time.Minute})
+
+				Expect(storage.Timeouts.Fetch).To(Equal(time.Minute))
+				Expect(storage.Timeouts.Filter).To(Equal(FilterTimeout))
+			})
+		})
+
+		Context("with canceled context", func() {
+			It("should not fetch documents", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err := storage.GetManyByFilter(ctx, bson.M{}, func() Document {
+					return &ExampleModel{}
+				})
+
+				Expect(err).NotTo(BeNil())
+			})
+		})
+
+		Context("with per-call timeouts", func() {
+			It("should use the timeouts of the call", func() {
+				ctx := WithTimeouts(context.Background(), Timeouts{Count: time.Nanosecond})
+
+				started := time.Now()
+				_, err := storage.CountByFilter(ctx, bson.M{})
+
+				Expect(err).NotTo(BeNil())
+				Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+				Expect(time.Since(started)).To(BeNumerically("<", time.Second))
+			})
+		})
+	})
+})
@@ -0,0 +1,232 @@
+package mongol
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Trackable is implemented by documents which remember their state after loading,
+// Save() uses the state to update only changed fields
+type Trackable interface {
+	Snapshot() bson.Raw
+	SetSnapshot(b bson.Raw)
+}
+
+var (
+	_ Trackable = (*ChangeTracking)(nil)
+)
+
+// ChangeTracking implements Trackable, embed it into the document with `bson:"-"` tag
+// GetOneByID() and GetOneByFilter() take a snapshot of such documents
+type ChangeTracking struct {
+	snapshot bson.Raw
+}
+
+// Snapshot() returns BSON of the document taken after the last load or save
+func (t *ChangeTracking) Snapshot() bson.Raw {
+	return t.snapshot
+}
+
+// SetSnapshot() sets BSON of the document, nil disables change tracking until the next load
+func (t *ChangeTracking) SetSnapshot(b bson.Raw) {
+	t.snapshot = b
+}
+
+// ChangedFields() returns dotted paths of the fields changed since the snapshot,
+// the result is empty for documents without a snapshot
+// Documents of clients with custom codecs are compared by ChangedFieldsWithRegistry()
+func ChangedFields(m Document) ([]string, error) {
+	return ChangedFieldsWithRegistry(bson.DefaultRegistry, m)
+}
+
+// ChangedFieldsWithRegistry() is like ChangedFields() but marshals the document with the registry,
+// see BaseCollection.BSONRegistry()
+func ChangedFieldsWithRegistry(r *bsoncodec.Registry, m Document) ([]string, error) {
+	t, ok := m.(Trackable)
+	if !ok || t.Snapshot() == nil {
+		return []string{}, nil
+	}
+
+	set, unset, err := changes(r, m, t.Snapshot())
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(set)+len(unset))
+	for i := range set {
+		fields = append(fields, set[i].Key)
+	}
+
+	for i := range unset {
+		fields = append(fields, unset[i].Key)
+	}
+
+	return fields, nil
+}
+
+// Save() updates fields of the Document changed since it was loaded by GetOneByID() or GetOneByFilter()
+// with $set and $unset, so concurrent updates of other fields are kept
+// Documents without a snapshot are updated like UpdateOne(), no request is sent if nothing has changed
+func (s *BaseCollection) Save(ctx context.Context, m Document, opts ...*options.UpdateOptions) (err error) {
+	defer s.wrapError(&err, SaveMethod, m)
+
+	if err := s.runBeforeHooks(ctx, SaveMethod); err != nil {
+		return err
+	}
+	defer s.runAfterHooks(ctx, SaveMethod)
+
+	t, ok := m.(Trackable)
+	if !ok || t.Snapshot() == nil {
+		if err := s.UpdateOne(s.nested(ctx), m, opts...); err != nil {
+			return err
+		}
+
+		return trackChanges(s.BSONRegistry(), m)
+	}
+
+	if set, unset, err := changes(s.BSONRegistry(), m, t.Snapshot()); err != nil || len(set)+len(unset) == 0 {
+		return err
+	}
+
+	m.SetupUpdatedAt()
+
+	if err := runBeforeUpdate(ctx, m); err != nil {
+		return err
+	}
+
+	set, unset, err := changes(s.BSONRegistry(), m, t.Snapshot())
+	if err != nil {
+		return err
+	}
+
+	set, unset = s.withoutRelationKeys(set), s.withoutRelationKeys(unset)
+
+	update := bson.D{}
+	if len(set) > 0 {
+		update = append(update, primitive.E{Key: "$set", Value: set})
+	}
+
+	if len(unset) > 0 {
+		update = append(update, primitive.E{Key: "$unset", Value: unset})
+	}
+
+	coll, filter, err := s.scope(ctx, bson.M{CollectionIDKey: bson.M{"$eq": documentKey(m)}})
+	if err != nil {
+		return err
+	}
+
+	var res *mongo.UpdateResult
+
+	err = s.retry(ctx, SaveMethod, true, func(ctx context.Context) (err error) {
+		res, err = coll.UpdateOne(ctx, filter, update, opts...)
+		return err
+	})
+	if err != nil {
+		return HandleError(err)
+	}
+
+	reportUpdateResult(ctx, res)
+
+	if res.MatchedCount == 0 && res.UpsertedID == nil {
+		return ErrDocumentNotFound
+	}
+
+	if err := trackChanges(s.BSONRegistry(), m); err != nil {
+		return err
+	}
+
+	return runAfterUpdate(ctx, m)
+}
+
+// trackChanges() takes a snapshot of Trackable documents
+func trackChanges(r *bsoncodec.Registry, doc interface{}) error {
+	t, ok := doc.(Trackable)
+	if !ok {
+		return nil
+	}
+
+	b, err := bson.MarshalWithRegistry(r, doc)
+	if err != nil {
+		return err
+	}
+
+	t.SetSnapshot(b)
+
+	return nil
+}
+
+// changes() returns $set and $unset fields of the document which differ from the snapshot
+func changes(r *bsoncodec.Registry, doc interface{}, snapshot bson.Raw) (set, unset bson.D, err error) {
+	b, err := bson.MarshalWithRegistry(r, doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	set, unset = bson.D{}, bson.D{}
+
+	if err := diffDocuments("", snapshot, b, &set, &unset); err != nil {
+		return nil, nil, err
+	}
+
+	return set, unset, nil
+}
+
+// diffDocuments() compares the documents field by field, embedded documents are compared recursively
+// and other values including arrays are replaced as a whole
+func diffDocuments(prefix string, before, after bson.Raw, set, unset *bson.D) error {
+	afterElems, err := after.Elements()
+	if err != nil {
+		return err
+	}
+
+	for _, el := range afterElems {
+		key := el.Key()
+		if prefix == "" && key == CollectionIDKey {
+			continue
+		}
+
+		val := el.Value()
+
+		old, err := before.LookupErr(key)
+		if err != nil {
+			*set = append(*set, primitive.E{Key: prefix + key, Value: val})
+			continue
+		}
+
+		if old.Type == bsontype.EmbeddedDocument && val.Type == bsontype.EmbeddedDocument {
+			if err := diffDocuments(prefix+key+".", old.Document(), val.Document(), set, unset); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !old.Equal(val) {
+			*set = append(*set, primitive.E{Key: prefix + key, Value: val})
+		}
+	}
+
+	beforeElems, err := before.Elements()
+	if err != nil {
+		return err
+	}
+
+	for _, el := range beforeElems {
+		key := el.Key()
+		if prefix == "" && key == CollectionIDKey {
+			continue
+		}
+
+		if _, err := after.LookupErr(key); err != nil {
+			*unset = append(*unset, primitive.E{Key: prefix + key, Value: ""})
+		}
+	}
+
+	return nil
+}
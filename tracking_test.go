@@ -0,0 +1,171 @@
+package mongol_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+
+	. "github.com/wajox/mongol"
+)
+
+type TrackedAddress struct {
+	City   string `bson:"city,omitempty"`
+	Street string `bson:"street,omitempty"`
+}
+
+type TrackedModel struct {
+	BaseDocument   `bson:",inline"`
+	ChangeTracking `bson:"-"`
+
+	Title   string          `bson:"title,omitempty"`
+	Counter int             `bson:"counter,omitempty"`
+	Tags    []string        `bson:"tags,omitempty"`
+	Address *TrackedAddress `bson:"address,omitempty"`
+}
+
+// nolint
+var _ = Describe("ChangeTracking", func() {
+	var (
+		storage *BaseCollection
+	)
+
+	BeforeEach(func() {
+		storage = newTestCollection("tracking_test")
+	})
+
+	Describe("ChangedFields()", func() {
+		It("should be empty for documents without a snapshot", func() {
+			fields, err := ChangedFields(&TrackedModel{Title: "title"})
+			Expect(err).To(BeNil())
+			Expect(fields).To(BeEmpty())
+
+			fields, err = ChangedFields(NewExampleModel())
+			Expect(err).To(BeNil())
+			Expect(fields).To(BeEmpty())
+		})
+
+		It("should return changed, removed and nested fields", func() {
+			m := &TrackedModel{
+				Title:   "title",
+				Counter: 1,
+				Tags:    []string{"a"},
+				Address: &TrackedAddress{City: "Berlin", Street: "Main"},
+			}
+
+			snapshot, err := bson.Marshal(m)
+			Expect(err).To(BeNil())
+			m.SetSnapshot(snapshot)
+
+			fields, err := ChangedFields(m)
+			Expect(err).To(BeNil())
+			Expect(fields).To(BeEmpty())
+
+			m.Title = "new title"
+			m.Counter = 0
+			m.Tags = append(m.Tags, "b")
+			m.Address.Street = "Second"
+
+			fields, err = ChangedFields(m)
+			Expect(err).To(BeNil())
+			Expect(fields).To(ConsistOf("title", "tags", "address.street", "counter"))
+		})
+
+		It("should marshal the document with the registry", func() {
+			// the registry stores strings in upper case
+			registry := bson.NewRegistryBuilder().
+				RegisterTypeEncoder(reflect.TypeOf(""), bsoncodec.ValueEncoderFunc(
+					func(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+						return vw.WriteString(strings.ToUpper(v.String()))
+					},
+				)).
+				Build()
+
+			m := &TrackedModel{Title: "title"}
+
+			snapshot, err := bson.MarshalWithRegistry(registry, m)
+			Expect(err).To(BeNil())
+			m.SetSnapshot(snapshot)
+
+			fields, err := ChangedFieldsWithRegistry(registry, m)
+			Expect(err).To(BeNil())
+			Expect(fields).To(BeEmpty())
+
+			fields, err = ChangedFields(m)
+			Expect(err).To(BeNil())
+			Expect(fields).To(ConsistOf("title"))
+		})
+
+		It("should not encode the snapshot", func() {
+			m := &TrackedModel{Title: "title"}
+			m.SetSnapshot(bson.Raw{})
+
+			b, err := bson.Marshal(m)
+			Expect(err).To(BeNil())
+
+			var doc bson.M
+			Expect(bson.Unmarshal(b, &doc)).To(Succeed())
+			Expect(doc).To(Equal(bson.M{"title": "title"}))
+		})
+	})
+
+	Describe(".Save()", func() {
+		It("should not send a request if nothing has changed", func() {
+			m := &TrackedModel{Title: "title"}
+			snapshot, err := bson.Marshal(m)
+			Expect(err).To(BeNil())
+			m.SetSnapshot(snapshot)
+
+			Expect(storage.Save(context.TODO(), m)).To(Succeed())
+			Expect(m.UpdatedAt.IsZero()).To(BeTrue())
+		})
+
+		Context("with loaded document", func() {
+			var (
+				m *TrackedModel
+			)
+
+			BeforeEach(func() {
+				inserted := &TrackedModel{Title: "title", Counter: 1, Address: &TrackedAddress{City: "Berlin"}}
+				id, err := storage.InsertOne(context.TODO(), inserted)
+				Expect(err).To(BeNil())
+
+				m = &TrackedModel{}
+				Expect(storage.GetOneByID(context.TODO(), id, m)).To(Succeed())
+				Expect(m.Snapshot()).NotTo(BeNil())
+			})
+
+			AfterEach(func() {
+				storage.DeleteAll(context.TODO())
+			})
+
+			It("should keep concurrent updates of other fields", func() {
+				_, err := storage.UpdateMany(
+					context.TODO(),
+					bson.M{"_id": m.ID},
+					bson.M{"$set": bson.M{"counter": 5}},
+				)
+				Expect(err).To(BeNil())
+
+				m.Title = "new title"
+				m.Address = nil
+				Expect(storage.Save(context.TODO(), m)).To(Succeed())
+
+				fields, err := ChangedFields(m)
+				Expect(err).To(BeNil())
+				Expect(fields).To(BeEmpty())
+
+				loaded := &TrackedModel{}
+				Expect(storage.GetOneByID(context.TODO(), m.GetHexID(), loaded)).To(Succeed())
+				Expect(loaded.Title).To(Equal("new title"))
+				Expect(loaded.Counter).To(Equal(5))
+				Expect(loaded.Address).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package mongol
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// UpdateBuilder
+type UpdateBuilder struct {
+	update   bson.M
+	resolver *FieldResolver
+	err      error
+}
+
+// NewUpdateBuilder() initializes a new UpdateBuilder
+func NewUpdateBuilder() *UpdateBuilder {
+	return &UpdateBuilder{
+		update: bson.M{},
+	}
+}
+
+// WithResolver() makes the builder accept Go field paths of the model, e.g. "Address.City",
+// unknown paths are reported by Err()
+func (ub *UpdateBuilder) WithResolver(r *FieldResolver) *UpdateBuilder {
+	ub.resolver = r
+
+	return ub
+}
+
+// Err() returns the first error of field paths resolution
+func (ub *UpdateBuilder) Err() error {
+	return ub.err
+}
+
+// GetUpdate() returns compiled update for *UpdateBuilder, unresolved field paths are kept as is, see Build()
+func (ub *UpdateBuilder) GetUpdate() bson.M {
+	return ub.update
+}
+
+// Build() returns compiled update or the first error of field paths resolution
+func (ub *UpdateBuilder) Build() (bson.M, error) {
+	if ub.err != nil {
+		return nil, ub.err
+	}
+
+	return ub.update, nil
+}
+
+// Set() implements $set operator of the update
+func (ub *UpdateBuilder) Set(key string, value interface{}) *UpdateBuilder {
+	return ub.add("$set", key, value)
+}
+
+// Unset() implements $unset operator of the update
+func (ub *UpdateBuilder) Unset(key string) *UpdateBuilder {
+	return ub.add("$unset", key, "")
+}
+
+// Inc() implements $inc operator of the update
+func (ub *UpdateBuilder) Inc(key string, value interface{}) *UpdateBuilder {
+	return ub.add("$inc", key, value)
+}
+
+// Push() implements $push operator of the update
+func (ub *UpdateBuilder) Push(key string, value interface{}) *UpdateBuilder {
+	return ub.add("$push", key, value)
+}
+
+// AddToSet() implements $addToSet operator of the update
+func (ub *UpdateBuilder) AddToSet(key string, value interface{}) *UpdateBuilder {
+	return ub.add("$addToSet", key, value)
+}
+
+// Pull() implements $pull operator of the update
+func (ub *UpdateBuilder) Pull(key string, value interface{}) *UpdateBuilder {
+	return ub.add("$pull", key, value)
+}
+
+func (ub *UpdateBuilder) add(operator, path string, value interface{}) *UpdateBuilder {
+	key, err := resolveKey(ub.resolver, path)
+	if err != nil && ub.err == nil {
+		ub.err = err
+	}
+
+	fields, ok := ub.update[operator].(bson.M)
+	if !ok {
+		fields = bson.M{}
+		ub.update[operator] = fields
+	}
+
+	fields[key] = value
+
+	return ub
+}
+
+// buildUpdate() returns the update of *UpdateBuilder, other updates are returned as is
+func buildUpdate(update interface{}) (interface{}, error) {
+	ub, ok := update.(*UpdateBuilder)
+	if !ok {
+		return update, nil
+	}
+
+	u, err := ub.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
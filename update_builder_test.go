@@ -0,0 +1,58 @@
+package mongol_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/wajox/mongol"
+)
+
+var _ = Describe("UpdateBuilder", func() {
+	It("should group fields by operators", func() {
+		update := NewUpdateBuilder().
+			Set("title", "new title").
+			Set("status", "active").
+			Unset("deleted_at").
+			Inc("views", 1).
+			Push("tags", "go").
+			AddToSet("labels", "new").
+			Pull("flags", "draft")
+
+		Expect(update.Err()).To(BeNil())
+		Expect(update.GetUpdate()).To(Equal(bson.M{
+			"$set":      bson.M{"title": "new title", "status": "active"},
+			"$unset":    bson.M{"deleted_at": ""},
+			"$inc":      bson.M{"views": 1},
+			"$push":     bson.M{"tags": "go"},
+			"$addToSet": bson.M{"labels": "new"},
+			"$pull":     bson.M{"flags": "draft"},
+		}))
+	})
+
+	Context("with field resolver", func() {
+		It("should resolve Go field paths", func() {
+			update := NewUpdateBuilder().WithResolver(NewFieldResolver(&ResolvedModel{})).
+				Set("Address.City", "Berlin").
+				Inc("Items.$.Price", 10)
+
+			Expect(update.Err()).To(BeNil())
+			Expect(update.GetUpdate()).To(Equal(bson.M{
+				"$set": bson.M{"addr.city": "Berlin"},
+				"$inc": bson.M{"items.$.price": 10},
+			}))
+		})
+
+		It("should report unknown fields", func() {
+			update := NewUpdateBuilder().WithResolver(NewFieldResolver(&ResolvedModel{})).Set("Adress.City", "Berlin")
+
+			Expect(errors.Is(update.Err(), ErrUnknownField)).To(BeTrue())
+
+			u, err := update.Build()
+			Expect(u).To(BeNil())
+			Expect(errors.Is(err, ErrUnknownField)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,84 @@
+package mongol
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WriteResult of update operations
+type WriteResult struct {
+	Matched  int64
+	Modified int64
+	// UpsertedID is set if the update inserted a new document
+	UpsertedID interface{}
+}
+
+func newWriteResult(res *mongo.UpdateResult) *WriteResult {
+	if res == nil {
+		return &WriteResult{}
+	}
+
+	return &WriteResult{
+		Matched:    res.MatchedCount,
+		Modified:   res.ModifiedCount,
+		UpsertedID: res.UpsertedID,
+	}
+}
+
+// SetTreatNoOpAsSuccess() makes UpdateOne() and UpdateManyByFilter() succeed
+// if the documents were matched but not modified
+func (s *BaseCollection) SetTreatNoOpAsSuccess(v bool) {
+	s.TreatNoOpAsSuccess = v
+}
+
+// UpdateOneWithResult() updates given Document like UpdateOne()
+// but returns counts of matched and modified documents instead of
+// ErrDocumentNotFound and ErrDocumentNotModified
+func (s *BaseCollection) UpdateOneWithResult(
+	ctx context.Context,
+	m Document,
+	opts ...*options.UpdateOptions,
+) (_ *WriteResult, err error) {
+	defer s.wrapError(&err, UpdateOneWithResultMethod, m)
+
+	if err := s.runBeforeHooks(ctx, UpdateOneWithResultMethod); err != nil {
+		return nil, err
+	}
+	defer s.runAfterHooks(ctx, UpdateOneWithResultMethod)
+
+	filter := bson.M{CollectionIDKey: bson.M{"$eq": documentKey(m)}}
+
+	return s.UpdateManyByFilterWithResult(s.nested(ctx), filter, m, opts...)
+}
+
+// UpdateManyByFilterWithResult() updates documents like UpdateManyByFilter()
+// but returns counts of matched and modified documents instead of
+// ErrDocumentNotFound and ErrDocumentNotModified
+// AfterUpdate callback runs if any document was matched or upserted
+func (s *BaseCollection) UpdateManyByFilterWithResult(
+	ctx context.Context,
+	filter interface{},
+	m Document,
+	opts ...*options.UpdateOptions,
+) (_ *WriteResult, err error) {
+	defer s.wrapError(&err, UpdateManyByFilterWithResultMethod, "")
+
+	if err := s.runBeforeHooks(ctx, UpdateManyByFilterWithResultMethod); err != nil {
+		return nil, err
+	}
+	defer s.runAfterHooks(ctx, UpdateManyByFilterWithResultMethod)
+
+	res, err := s.updateByFilter(ctx, UpdateManyByFilterWithResultMethod, filter, m, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Matched == 0 && res.UpsertedID == nil {
+		return res, nil
+	}
+
+	return res, runAfterUpdate(ctx, m)
+}
@@ -0,0 +1,95 @@
+package mongol_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/wajox/mongol"
+)
+
+// nolint
+var _ = Describe("WriteResult", func() {
+	var (
+		storage *BaseCollection
+	)
+
+	BeforeEach(func() {
+		storage = newTestCollection("write_result_test")
+	})
+
+	Describe(".SetTreatNoOpAsSuccess()", func() {
+		It("should set the option", func() {
+			Expect(storage.TreatNoOpAsSuccess).To(BeFalse())
+
+			storage.SetTreatNoOpAsSuccess(true)
+			Expect(storage.TreatNoOpAsSuccess).To(BeTrue())
+		})
+	})
+
+	Describe(".UpdateOneWithResult()", func() {
+		It("should wrap errors with the method name", func() {
+			storage.SetTenancy(&Tenancy{Mode: TenantPerField})
+
+			m := NewExampleModel()
+			m.SetHexID("5f2b7e5c0000000000000000")
+
+			res, err := storage.UpdateOneWithResult(context.TODO(), m)
+			Expect(res).To(BeNil())
+
+			var opErr *OperationError
+			Expect(errors.As(err, &opErr)).To(BeTrue())
+			Expect(opErr.Method).To(Equal(UpdateOneWithResultMethod))
+			Expect(errors.Is(err, ErrTenantMissing)).To(BeTrue())
+		})
+	})
+
+	Describe("no-op updates", func() {
+		var (
+			m      *ExampleModel
+			update *ExampleModelWithoutTimestamps
+		)
+
+		BeforeEach(func() {
+			m = NewExampleModel()
+			_, err := storage.InsertOne(context.TODO(), m)
+			Expect(err).To(BeNil())
+
+			update = &ExampleModelWithoutTimestamps{Title: m.Title}
+			update.SetHexID(m.GetHexID())
+		})
+
+		AfterEach(func() {
+			storage.DeleteAll(context.TODO())
+		})
+
+		It("should return ErrDocumentNotModified by default", func() {
+			err := storage.UpdateOne(context.TODO(), update)
+			Expect(errors.Is(err, ErrDocumentNotModified)).To(BeTrue())
+		})
+
+		It("should succeed with TreatNoOpAsSuccess", func() {
+			storage.SetTreatNoOpAsSuccess(true)
+
+			Expect(storage.UpdateOne(context.TODO(), update)).To(BeNil())
+		})
+
+		It("should return counts of matched and modified documents", func() {
+			res, err := storage.UpdateOneWithResult(context.TODO(), update)
+			Expect(err).To(BeNil())
+			Expect(res.Matched).To(Equal(int64(1)))
+			Expect(res.Modified).To(Equal(int64(0)))
+			Expect(res.UpsertedID).To(BeNil())
+		})
+
+		It("should not return ErrDocumentNotFound", func() {
+			update.SetHexID("5f2b7e5c0000000000000000")
+
+			res, err := storage.UpdateOneWithResult(context.TODO(), update)
+			Expect(err).To(BeNil())
+			Expect(res.Matched).To(Equal(int64(0)))
+		})
+	})
+})